@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// speechKey identifies a synthesized recording: the same text in another
+// language or at another rate sounds different and gets its own file
+type speechKey struct {
+	text string
+	lang string
+	rate int
+}
+
+// cachingSpeaker speaks through a synthesizer, but renders every text to
+// an audio file only once and replays that file afterwards
+// Starting 'say' takes a moment each time, so repeating a word with TAB
+// feels much snappier when the recording is simply played again
+type cachingSpeaker struct {
+	engine synthesizer
+	player string // Command that plays the cached files (see soundPlayers)
+	dir    string // Temporary directory holding the recordings
+
+	// Speak runs in background commands, so the map needs a lock
+	mu    sync.Mutex
+	files map[speechKey]string
+}
+
+// newCachingSpeaker creates a cache in a fresh temporary directory
+// Call Close at the end of the session to remove the recordings
+func newCachingSpeaker(engine synthesizer, player string) (*cachingSpeaker, error) {
+	dir, err := os.MkdirTemp("", "dictation-speech")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create speech cache: %w", err)
+	}
+	return &cachingSpeaker{
+		engine: engine,
+		player: player,
+		dir:    dir,
+		files:  make(map[speechKey]string),
+	}, nil
+}
+
+// Speak implements Speaker
+// If synthesizing or playing fails, the text is spoken directly instead
+func (c *cachingSpeaker) Speak(text, langCode string, rate int) error {
+	path, err := c.recording(speechKey{text: text, lang: langCode, rate: rate})
+	if err == nil {
+		err = playAudioFile(c.player, path)
+	}
+	if err != nil {
+		return c.engine.Speak(text, langCode, rate)
+	}
+	return nil
+}
+
+// recording returns the audio file for key, synthesizing it on first use
+func (c *cachingSpeaker) recording(key speechKey) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if path, ok := c.files[key]; ok {
+		return path, nil
+	}
+	// Numbered file names avoid problems with characters in the text
+	path := filepath.Join(c.dir, fmt.Sprintf("%d.aiff", len(c.files)))
+	if err := c.engine.Synthesize(key.text, key.lang, path, key.rate); err != nil {
+		return "", err
+	}
+	c.files[key] = path
+	return path, nil
+}
+
+// Close removes the cached recordings
+func (c *cachingSpeaker) Close() error {
+	return os.RemoveAll(c.dir)
+}
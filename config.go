@@ -2,7 +2,11 @@ package main
 
 import (
 	"fmt"
+	"io"
+	"net/http"
 	"os"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -11,41 +15,632 @@ import (
 // In Go, structs define data structures with named fields
 // The `yaml:"words"` tag tells the YAML parser which field to map to
 type Config struct {
+	Title    string   `yaml:"title"`    // Optional title of the word list (e.g., "Week 3: ck and tz")
+	Author   string   `yaml:"author"`   // Optional author of the word list (e.g., the teacher's name)
 	Language string   `yaml:"language"` // Language code (e.g., "en", "de", "fr")
-	Words    []string `yaml:"words"`
+	Words    []WordEntry `yaml:"words"` // Plain words or entries with metadata
+	
+	// Definitions maps words to their meaning, which can be spoken on request
+	Definitions map[string]string `yaml:"definitions"`
+	
+	// Phonetics maps words to their IPA transcription (e.g., "Haus": "haʊs"),
+	// which learners can show as a hint
+	Phonetics map[string]string `yaml:"phonetics"`
+	
+	// Rhymes maps tricky words to a rhyming or similar word (e.g., "Haus":
+	// "Maus"), which learners can hear as a hint
+	Rhymes map[string]string `yaml:"rhymes"`
+	
+	// DedupeWords removes words that were already listed, exactly or only
+	// differing in case ("haus" after "Haus"); otherwise they are only
+	// reported
+	DedupeWords bool `yaml:"dedupe_words"`
+	
+	// Duplicates lists the duplicate words found while loading, for a warning
+	Duplicates duplicateWarning `yaml:"-"`
+	
+	// ShowDiff controls whether incorrect answers show the character-level diff
+	// When false, only the correct spelling is revealed (listening mode)
+	ShowDiff bool `yaml:"show_diff"`
+	
+	// MarkTranspositions highlights two swapped neighbor letters in the
+	// diff ("Huas" for "Haus") with their own marker and a note
+	MarkTranspositions bool `yaml:"mark_transpositions"`
+	
+	// IgnoreCase accepts answers in any upper and lower case ("haus" for
+	// "Haus"), e.g. for young learners who don't know the rules yet
+	IgnoreCase bool `yaml:"ignore_case"`
+	
+	// DiacriticsOptional accepts answers that only differ in accents or
+	// umlauts (e.g., "Mädchen" typed as "Madchen") for beginners
+	DiacriticsOptional bool `yaml:"diacritics_optional"`
+	
+	// SzEquivalence accepts "ss" for "ß" and vice versa in German lists
+	// ("Strasse" for "Straße"); the diff still shows the proper spelling
+	SzEquivalence bool `yaml:"sz_equivalence"`
+	
+	// Numbers accepts numerals and spelled-out numbers from 0 to 20 for
+	// each other ("3" or "three"); numerals are spoken in their spelled form
+	Numbers bool `yaml:"numbers"`
+	
+	// IgnoreTrailingPunctuation accepts phrases with or without a final
+	// ".", "!" or "?"; the diff still shows the difference
+	IgnoreTrailingPunctuation bool `yaml:"ignore_trailing_punctuation"`
+	
+	// CollapseWhitespace treats runs of spaces inside an answer as a single
+	// space, so "the  fox" counts as "the fox"
+	CollapseWhitespace bool `yaml:"collapse_whitespace"`
+	
+	// TrimQuotes ignores one pair of quotes around an answer ("\"Haus\""),
+	// straight or typographic, as added by copy-paste or smart keyboards
+	TrimQuotes bool `yaml:"trim_quotes"`
+	
+	// FoldLigatures accepts typographic variants: "oe" for "œ", "ae" for
+	// "æ" and any apostrophe ("’", "ʼ", "´") for the straight one
+	FoldLigatures bool `yaml:"fold_ligatures"`
+	
+	// ShowLength shows the number of letters of the word (or of each word
+	// of a phrase) as a hint, without revealing any of them
+	ShowLength bool `yaml:"show_length"`
+	
+	// ShowTypingHints shows how to type the special characters of the word
+	// (umlauts, accents) on a US keyboard, for the operating system in use
+	ShowTypingHints bool `yaml:"show_typing_hints"`
+	
+	// SiblingHint points out when a wrong answer is another word of the
+	// list ("too" typed for "to"), a common mix-up with homophones
+	SiblingHint bool `yaml:"sibling_hint"`
+	
+	// CapitalizationHint encourages instead of scolding when only upper and
+	// lower case are wrong ("haus" for "Haus"), e.g. for German nouns
+	CapitalizationHint bool `yaml:"capitalization_hint"`
+	
+	// AutoRevealAfter shows the correct spelling prominently once a word
+	// has been misspelled this many times (0 = disabled)
+	AutoRevealAfter int `yaml:"auto_reveal_after"`
+	
+	// MaxAttempts gives up on a word after this many wrong answers: it is
+	// spelled out aloud letter by letter and not asked again (0 = never)
+	MaxAttempts int `yaml:"max_attempts"`
+	
+	// RTL renders words right-to-left (derived from the language if unset)
+	RTL bool `yaml:"rtl"`
+	
+	// MaskInput hides typed characters behind dots until the answer is
+	// submitted, so learners rely on what they heard (blind type-along)
+	MaskInput bool `yaml:"mask_input"`
+	
+	// AllowTeacherOverride enables Ctrl+G on the incorrect dialog, which
+	// counts the answer as correct after all (for live sessions where the
+	// teacher accepts an answer)
+	AllowTeacherOverride bool `yaml:"allow_teacher_override"`
+	
+	// ConfirmSubmit asks before an answer is checked, so an Enter hit too
+	// early doesn't count as a wrong attempt
+	ConfirmSubmit bool `yaml:"confirm_submit"`
+	
+	// RequireArticle makes learners type the article as well ("das Haus")
+	// for entries that have one; otherwise only the word is checked
+	RequireArticle bool `yaml:"require_article"`
+	
+	// OptionalLeadingArticle accepts answers with or without a leading
+	// article ("cat" or "the cat"), for vocabulary spoken with its article
+	OptionalLeadingArticle bool `yaml:"optional_leading_article"`
+	
+	// RetryMode decides when a misspelled word is practiced again:
+	// "requeue" (default) moves it to the end of the queue, "immediate"
+	// asks for it again right away up to ImmediateRetries times before
+	// falling back to the end of the queue
+	RetryMode        string `yaml:"retry_mode"`
+	ImmediateRetries int    `yaml:"immediate_retries"`
+	
+	// AutoAdvance closes the dialog after correct answers on its own after
+	// this delay (e.g., "1.5s"); incorrect answers still wait for Enter so
+	// the diff can be studied (0 = always wait)
+	AutoAdvance time.Duration `yaml:"auto_advance"`
+	
+	// InterWordPause is a short breather between closing the feedback
+	// dialog and the next word, while "Next word..." is shown (0 = none)
+	InterWordPause time.Duration `yaml:"inter_word_pause"`
+	
+	// QuietCorrect skips the dialog after correct answers: a check mark
+	// flashes in the title bar and the next word follows right away
+	QuietCorrect bool `yaml:"quiet_correct"`
+	
+	// FlashFeedback briefly colors the whole title bar green or red when
+	// an answer is checked, for feedback at a glance
+	FlashFeedback bool `yaml:"flash_feedback"`
+	
+	// Mode selects the exercise: "dictation" (default) speaks each word,
+	// "memory" shows it for FlashDuration and then hides it, without audio,
+	// "unscramble" speaks it and shows its letters in random order, "copy"
+	// speaks it and keeps it on screen while it is typed, "choose" speaks
+	// it and lets the learner pick its spelling from Choices options
+	Mode          string        `yaml:"mode"`
+	FlashDuration time.Duration `yaml:"flash_duration"`
+	Choices       int           `yaml:"choices"`
+	
+	// TimeLimit gives the learner this long to type each word (0 = no
+	// limit); when the time is up, whatever was typed so far is checked
+	// TimeWarning says "hurry" once when only this much time is left
+	TimeLimit   time.Duration `yaml:"time_limit"`
+	TimeWarning time.Duration `yaml:"time_warning"`
+	
+	// ChunkPhrases speaks phrases word by word with short pauses, so long
+	// phrases aren't read too fast to write down
+	ChunkPhrases bool `yaml:"chunk_phrases"`
+	
+	// RateJitter varies the speech rate of each word randomly by up to
+	// this many words per minute, faster or slower (0 = always the same)
+	RateJitter int `yaml:"rate_jitter"`
+	
+	// SoundEffects plays a short sound when the feedback dialog appears
+	// (off by default; needs afplay on macOS or aplay on Linux)
+	SoundEffects bool `yaml:"sound_effects"`
+	
+	// SinglePass presents every word exactly once, even when misspelled,
+	// so the accuracy reflects first-pass performance (assessments)
+	SinglePass bool `yaml:"single_pass"`
+	
+	// RecapMissed practices every word that was missed at least once again
+	// at the end, in a recap round with its own accuracy in the summary
+	RecapMissed bool `yaml:"recap_missed"`
+	
+	// MasteryLoop keeps drilling the words that were never spelled
+	// correctly after the last loop, in reshuffled rounds, until each was
+	// right once; words given up on after MaxAttempts are left out
+	MasteryLoop bool `yaml:"mastery_loop"`
+	
+	// Focus lists hard words that always come first in the session,
+	// before the shuffled rest (also set via --focus)
+	Focus []string `yaml:"focus"`
+	
+	// SortCompletedWords lists the correctly spelled words in the title bar
+	// alphabetically (by the rules of the list language) instead of in
+	// the order they were completed
+	SortCompletedWords bool `yaml:"sort_completed_words"`
+	
+	// GroupSummaryByInitial lists the missed words in the summary grouped
+	// by their first letter, so teachers see which initial sounds are hard
+	GroupSummaryByInitial bool `yaml:"group_summary_by_initial"`
+	
+	// ShowTranscript shows the latest answers in a pane to the right, e.g.
+	// for a teacher watching; only when the terminal is wide enough
+	ShowTranscript bool `yaml:"show_transcript"`
+	
+	// ShowDifficulty shows the difficulty of the current word next to its
+	// number in the title bar ("Word 3 (hard)"), if the entry has one
+	ShowDifficulty bool `yaml:"show_difficulty"`
+	
+	// SkipIntro starts with the first word right away instead of showing
+	// the intro screen that waits for Enter
+	SkipIntro bool `yaml:"skip_intro"`
+	
+	// SpeakIntro announces the session aloud before the first word, with
+	// the same localized instructions as the intro screen
+	SpeakIntro bool `yaml:"speak_intro"`
+	
+	// Scoring sets the points for each kind of answer
+	Scoring Scoring `yaml:"scoring"`
+	
+	// Voices replaces the built-in macOS voice of a language; with a list
+	// of voices, one is picked at random for every word
+	Voices map[string]voiceList `yaml:"voices"`
+	
+	// AudioDevice sends the speech of 'say' to another output device (an
+	// ID or name from "say -a '?'"), Volume sets its loudness from 0 to 1
+	// (0 keeps the system volume)
+	AudioDevice string  `yaml:"audio_device"`
+	Volume      float64 `yaml:"volume"`
+	
+	// PreRollSilence starts the speech of 'say' with a short silence, for
+	// audio devices that clip the first syllable while waking up
+	PreRollSilence bool `yaml:"pre_roll_silence"`
+	
+	// Theme customizes cursor and colors for different terminal themes
+	Theme ThemeConfig `yaml:"theme"`
+	
+	// NoShuffle presents the words in config order, in every loop
+	// Set via the --no-shuffle command-line flag
+	NoShuffle bool `yaml:"-"`
+	
+	// Ramp orders the words from easy to hard, shuffled within each level
+	// Set via the --ramp command-line flag
+	Ramp bool `yaml:"-"`
+	
+	// TargetAccuracy ends the session early once the accuracy reaches this
+	// percentage after at least MinWords answers (0 = never)
+	// Set via the --target-accuracy and --min-words command-line flags
+	TargetAccuracy int `yaml:"-"`
+	MinWords       int `yaml:"-"`
+	
+	// Preview speaks the whole list once in order before the practice
+	// Set via the --preview command-line flag
+	Preview bool `yaml:"-"`
+	
+	// Plain uses a line-by-line prompt instead of the TUI, for screen readers
+	// Set via the --plain command-line flag
+	Plain bool `yaml:"-"`
+	
+	// Loops is the number of passes through the word list (0 = until quit)
+	// Set via the --loop command-line flag rather than the YAML file
+	Loops int `yaml:"-"`
+}
+
+// defaultConfig returns a Config with all optional settings at their defaults
+// Fields missing from the YAML file keep these values after parsing
+func defaultConfig() Config {
+	return Config{
+		ShowDiff:           true,
+		SiblingHint:        true,
+		CapitalizationHint: true,
+		ShowDifficulty:     true,
+		MarkTranspositions: true,
+		Loops:              1,
+		RetryMode:          retryRequeue,
+		ImmediateRetries:   2,
+		Mode:               modeDictation,
+		FlashDuration:      2 * time.Second,
+		Choices:            4,
+		InterWordPause:     500 * time.Millisecond,
+		TimeWarning:        3 * time.Second,
+		Scoring:            defaultScoring(),
+	}
+}
+
+// maxRateJitter keeps jittered rates well above the slow replay rate, so
+// the normal and slow speeds stay distinguishable
+const maxRateJitter = 50
+
+// Retry modes for misspelled words
+const (
+	retryRequeue   = "requeue"
+	retryImmediate = "immediate"
+)
+
+// Exercise modes
+const (
+	modeDictation  = "dictation"  // Listen and type
+	modeMemory     = "memory"     // Read, memorize and type
+	modeUnscramble = "unscramble" // Listen, put the shown letters in order and type
+	modeCopy       = "copy"       // Listen and copy the shown word
+	modeChoose     = "choose"     // Listen and pick the right spelling
+)
+
+// hasVoiceChoice reports whether a language has several voices to pick
+// from at random
+func (c *Config) hasVoiceChoice() bool {
+	for _, voices := range c.Voices {
+		if len(voices) > 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// voiceList is one or more voice names for a language
+// In YAML it is either a single name ("de: Anna") or a list
+// ("de: [Anna, Petra, Markus]")
+type voiceList []string
+
+// UnmarshalYAML accepts a single voice name as a list with one voice
+func (v *voiceList) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		var voice string
+		if err := node.Decode(&voice); err != nil {
+			return err
+		}
+		*v = voiceList{voice}
+		return nil
+	}
+	// The conversion to *[]string decodes without calling this method again
+	return node.Decode((*[]string)(v))
+}
+
+// rtlLanguages lists language codes written from right to left
+var rtlLanguages = map[string]bool{
+	"ar": true, // Arabic
+	"fa": true, // Persian
+	"he": true, // Hebrew
+	"ur": true, // Urdu
+	"yi": true, // Yiddish
+}
+
+// isRTL reports whether words should be rendered right-to-left
+func (c *Config) isRTL() bool {
+	return c.isRTLFor(c.Language)
+}
+
+// isRTLFor is like isRTL for a word in the given language
+// Words can have their own language in mixed lists
+func (c *Config) isRTLFor(language string) bool {
+	return c.RTL || rtlLanguages[language]
 }
 
 // loadConfig reads and parses the YAML configuration file
 // Functions in Go can return multiple values - here we return a pointer
 // to Config and an error. This is the idiomatic Go error handling pattern.
 func loadConfig(filename string) (*Config, error) {
-	// os.ReadFile reads the entire file into a byte slice
-	data, err := os.ReadFile(filename)
-	if err != nil {
-		// fmt.Errorf creates a formatted error with context
-		// The %w verb wraps the original error for error unwrapping
-		return nil, fmt.Errorf("failed to read config file: %w", err)
-	}
+	return loadConfigs([]string{filename})
+}
+
+// loadConfigFromURL downloads and parses a word list shared over HTTP(S)
+// The body may be YAML or JSON, since JSON is valid YAML
+func loadConfigFromURL(url string) (*Config, error) {
+	return loadConfigs([]string{url})
+}
 
-	// Create an empty Config struct
-	var config Config
+// loadConfigs reads several YAML configuration files and merges them
+// The word lists are concatenated in order; a word of an earlier file is
+// only kept once
+// Title and options are taken from the first file
+// All files that set a language must agree on it
+func loadConfigs(filenames []string) (*Config, error) {
+	var merged *Config
+	earlier := make(map[string]bool)
 	
-	// yaml.Unmarshal parses YAML bytes into our struct
-	// The & operator gets the address (pointer) of config
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	for _, filename := range filenames {
+		config, err := readConfigFile(filename)
+		if err != nil {
+			return nil, err
+		}
+		
+		words := config.Words
+		if merged != nil {
+			// Definitions and hints of later files complete those of earlier ones
+			merged.Definitions = mergeWordMap(merged.Definitions, config.Definitions)
+			merged.Phonetics = mergeWordMap(merged.Phonetics, config.Phonetics)
+			merged.Rhymes = mergeWordMap(merged.Rhymes, config.Rhymes)
+		}
+		if merged == nil {
+			// The first file provides title and options for the session
+			merged = config
+			merged.Words = nil
+		} else if config.Language != "" {
+			if merged.Language == "" {
+				merged.Language = config.Language
+			} else if config.Language != merged.Language {
+				return nil, fmt.Errorf("conflicting languages: %q and %q (in %s)", merged.Language, config.Language, filename)
+			}
+		}
+		
+		// Topic lists overlap, so words of earlier files are merged;
+		// repeats within a file are duplicates and handled below
+		for _, entry := range words {
+			if !earlier[entry.Word] {
+				merged.Words = append(merged.Words, entry)
+			}
+		}
+		for _, entry := range words {
+			earlier[entry.Word] = true
+		}
 	}
-
+	
 	// Validate that we have at least one word
-	if len(config.Words) == 0 {
+	if merged == nil || len(merged.Words) == 0 {
 		return nil, fmt.Errorf("no words found in config file")
 	}
 
+	// Duplicates are usually copy-paste mistakes, and words that only
+	// differ in case are probably typos; maps make cheap "have we seen
+	// this?" checks
+	var exactDups, caseDups []string
+	seen := make(map[string]bool)
+	seenFolded := make(map[string]bool)
+	for _, word := range merged.wordList() {
+		folded := strings.ToLower(word)
+		if seen[word] {
+			exactDups = append(exactDups, word)
+		} else if seenFolded[folded] {
+			caseDups = append(caseDups, word)
+		}
+		seen[word] = true
+		seenFolded[folded] = true
+	}
+	// Only the first occurrence of each unique word is kept
+	if merged.DedupeWords && (len(exactDups) > 0 || len(caseDups) > 0) {
+		unique, _ := dedupeWords(merged.wordList())
+		keep := make(map[string]bool, len(unique))
+		for _, word := range unique {
+			keep[word] = true
+		}
+		kept := merged.Words[:0]
+		for _, entry := range merged.Words {
+			if keep[entry.Word] {
+				kept = append(kept, entry)
+				delete(keep, entry.Word)
+			}
+		}
+		merged.Words = kept
+	}
+	merged.Duplicates = duplicateWarning{
+		Exact:    exactDups,
+		Variants: caseDups,
+		Removed:  merged.DedupeWords,
+	}
+	
+	for _, entry := range merged.Words {
+		if entry.Weight < 0 {
+			return nil, fmt.Errorf("invalid weight %v for %q (must not be negative)", entry.Weight, entry.Word)
+		}
+		if entry.difficultyRank() < 0 {
+			return nil, fmt.Errorf("invalid difficulty %q for %q (use %s)", entry.Difficulty, entry.Word, strings.Join(difficultyLevels, ", "))
+		}
+	}
+	
+	if merged.RetryMode != retryRequeue && merged.RetryMode != retryImmediate {
+		return nil, fmt.Errorf("invalid retry_mode %q (use %q or %q)", merged.RetryMode, retryRequeue, retryImmediate)
+	}
+	
+	if merged.RateJitter < 0 || merged.RateJitter > maxRateJitter {
+		return nil, fmt.Errorf("invalid rate_jitter %d (use 0 to %d)", merged.RateJitter, maxRateJitter)
+	}
+	
+	if merged.Volume < 0 || merged.Volume > 1 {
+		return nil, fmt.Errorf("invalid volume %v (use a value from 0 to 1)", merged.Volume)
+	}
+	
+	switch merged.Mode {
+	case modeDictation, modeMemory, modeUnscramble, modeCopy, modeChoose:
+		// Known modes
+	default:
+		return nil, fmt.Errorf("invalid mode %q (use %q, %q, %q, %q or %q)", merged.Mode, modeDictation, modeMemory, modeUnscramble, modeCopy, modeChoose)
+	}
+	if merged.Choices < 2 {
+		return nil, fmt.Errorf("invalid choices %d (at least 2 are needed)", merged.Choices)
+	}
+	
+	// Reject invalid colors early rather than rendering garbage later
+	if err := validateTheme(merged.Theme); err != nil {
+		return nil, err
+	}
+
 	// Set default language if not specified
-	if config.Language == "" {
-		config.Language = "en"  // Default to English
+	if merged.Language == "" {
+		merged.Language = "en"  // Default to English
+	}
+	
+	// Numerals are spoken spelled out, unless the entry says otherwise
+	if merged.Numbers {
+		for i, entry := range merged.Words {
+			spelled := spellNumbers(entry.Word, entry.languageOr(merged.Language))
+			if entry.Pronunciation == "" && spelled != entry.Word {
+				merged.Words[i].Pronunciation = spelled
+			}
+		}
+	}
+
+	return merged, nil
+}
+
+// mergeWordMap adds the entries of extra that are missing in base
+// Maps are reference types, so base is changed in place; a nil base is
+// created first because writing to a nil map panics
+func mergeWordMap(base, extra map[string]string) map[string]string {
+	for word, value := range extra {
+		if _, ok := base[word]; !ok {
+			if base == nil {
+				base = make(map[string]string)
+			}
+			base[word] = value
+		}
+	}
+	return base
+}
+
+// dedupeWords removes words that were already listed, ignoring case
+// The first occurrence is kept, so "Haus, Buch, haus" becomes "Haus, Buch";
+// dups are the later occurrences that were dropped, in list order
+func dedupeWords(words []string) (unique []string, dups []string) {
+	seen := make(map[string]bool, len(words))
+	for _, word := range words {
+		key := strings.ToLower(word)
+		if seen[key] {
+			dups = append(dups, word)
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, word)
+	}
+	return unique, dups
+}
+
+// duplicateWarning describes the duplicate words of a word list
+// main prints it as a warning, since duplicates are usually copy-paste
+// mistakes rather than errors
+type duplicateWarning struct {
+	Exact    []string // Words that were already listed
+	Variants []string // Words that differ from an earlier one only in case
+	Removed  bool     // Whether the duplicates were removed (DedupeWords)
+}
+
+// empty reports whether no duplicates were found
+func (w duplicateWarning) empty() bool {
+	return len(w.Exact) == 0 && len(w.Variants) == 0
+}
+
+// String implements fmt.Stringer, so the warning can be printed directly
+func (w duplicateWarning) String() string {
+	action := "set dedupe_words: true to remove them"
+	if w.Removed {
+		action = "removed"
+	}
+	var parts []string
+	if len(w.Exact) > 0 {
+		parts = append(parts, fmt.Sprintf("duplicate words: %s (%s)", strings.Join(w.Exact, ", "), action))
+	}
+	if len(w.Variants) > 0 {
+		parts = append(parts, fmt.Sprintf("words that only differ in case: %s (%s)", strings.Join(w.Variants, ", "), action))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// readConfigFile reads a single YAML configuration file without validating it
+// filename can also be an http:// or https:// URL
+func readConfigFile(filename string) (*Config, error) {
+	var data []byte
+	var err error
+	if isURL(filename) {
+		data, err = fetchConfig(filename)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		// os.ReadFile reads the entire file into a byte slice
+		data, err = os.ReadFile(filename)
+		if err != nil {
+			// fmt.Errorf creates a formatted error with context
+			// The %w verb wraps the original error for error unwrapping
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+	}
+
+	// Start from the defaults so that omitted fields keep sensible values
+	config := defaultConfig()
+	
+	// yaml.Unmarshal parses YAML bytes into our struct
+	// The & operator gets the address (pointer) of config
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML in %s: %w", filename, err)
 	}
 
 	// Return a pointer to the config (&config) and nil error
 	return &config, nil
 }
+
+// Limits for downloading word lists
+const (
+	configFetchTimeout = 10 * time.Second
+	maxConfigSize      = 1 << 20 // 1 MiB is plenty for a word list
+)
+
+// isURL reports whether a config path refers to a remote word list
+func isURL(path string) bool {
+	return strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://")
+}
+
+// fetchConfig downloads a config file with a timeout and a size limit,
+// so a slow or misconfigured server can't hang or flood the app
+func fetchConfig(url string) ([]byte, error) {
+	client := &http.Client{Timeout: configFetchTimeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download word list from %s: %w", url, err)
+	}
+	// defer runs when the function returns, so the body is always closed
+	defer resp.Body.Close()
+	
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download word list from %s: server responded %s", url, resp.Status)
+	}
+	
+	// Read one byte more than allowed to detect oversized lists
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxConfigSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download word list from %s: %w", url, err)
+	}
+	if len(data) > maxConfigSize {
+		return nil, fmt.Errorf("word list at %s is larger than %d bytes", url, maxConfigSize)
+	}
+	return data, nil
+}
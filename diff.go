@@ -39,16 +39,143 @@ var (
 			Foreground(lipgloss.Color("9")).  // Red
 			Bold(true)
 	
+	// Near-miss character style (when an almost correct answer differs)
+	nearMissCharStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("214")).  // Amber
+				Bold(true)
+	
 	// Turquoise style for correctly spelled words list
 	turquoiseStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("6"))  // Turquoise/Cyan
 )
 
+// diffSeverity tells how far a wrong answer is from the correct word
+type diffSeverity int
+
+const (
+	severityWrong    diffSeverity = iota // Clearly wrong: differences in red
+	severityNearMiss                     // Almost right: differences in amber
+)
+
+// nearMissDistance is the largest edit distance that still counts as "almost"
+const nearMissDistance = 2
+
+// severityFor classifies an answer by its edit distance to the correct word
+func severityFor(userInput, correctWord string) diffSeverity {
+	distance := editDistance(userInput, correctWord)
+	if distance >= 1 && distance <= nearMissDistance {
+		return severityNearMiss
+	}
+	return severityWrong
+}
+
+// editDistance returns the Levenshtein distance between two strings: the
+// number of single-character insertions, deletions and substitutions
+// needed to turn a into b. It works on runes so "ä" counts as one character
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	
+	// Only the previous row of the distance matrix is needed at any time
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// diffOptions holds optional settings for formatWordDiff
+type diffOptions struct {
+	styles         styleSet
+	rtl            bool         // Right-to-left script (Hebrew, Arabic, ...)
+	severity       diffSeverity // Chooses the style of differing characters
+	maxWidth       int          // Wrap long phrases to this width (0 = no wrapping)
+	transpositions bool         // Mark swapped neighbor letters with swapMarker
+}
+
+// diffOption configures formatWordDiff
+// Functional options keep the common call short while allowing customization
+type diffOption func(*diffOptions)
+
+// withStyles renders the diff using the given (themed) styles
+func withStyles(styles styleSet) diffOption {
+	return func(o *diffOptions) {
+		o.styles = styles
+	}
+}
+
+// withRTL renders the diff for right-to-left scripts
+// Characters are laid out from right to left and labels move to the right
+func withRTL(rtl bool) diffOption {
+	return func(o *diffOptions) {
+		o.rtl = rtl
+	}
+}
+
+// withSeverity highlights the differences according to how close the answer is
+func withSeverity(severity diffSeverity) diffOption {
+	return func(o *diffOptions) {
+		o.severity = severity
+	}
+}
+
+// withMaxWidth wraps long phrases so no line is wider than width
+// The characters continue in aligned blocks below the first one
+func withMaxWidth(width int) diffOption {
+	return func(o *diffOptions) {
+		o.maxWidth = width
+	}
+}
+
+// withTranspositions marks two swapped neighbor letters ("Huas" for "Haus")
+// with swapMarker instead of two plain differences, and adds a note
+func withTranspositions(enabled bool) diffOption {
+	return func(o *diffOptions) {
+		o.transpositions = enabled
+	}
+}
+
+// swapMarker marks both letters of a transposition in the diff line
+const swapMarker = "⇄"
+
+// findTranspositions returns the positions i where the input has the
+// letters i and i+1 of the correct word in swapped order
+// Pairs don't overlap, so "abc" typed as "bca" isn't counted twice
+func findTranspositions(userRunes, correctRunes []rune) []int {
+	var swaps []int
+	n := min(len(userRunes), len(correctRunes))
+	for i := 0; i+1 < n; i++ {
+		if userRunes[i] != correctRunes[i] && userRunes[i] != userRunes[i+1] &&
+			userRunes[i] == correctRunes[i+1] && userRunes[i+1] == correctRunes[i] {
+			swaps = append(swaps, i)
+			i++ // Skip the second letter of the pair
+		}
+	}
+	return swaps
+}
+
 // formatWordDiff creates a visual comparison between user input and correct word
 // It shows both words side by side with color-coded indicators for matches and differences
 // This helps students see exactly where they made mistakes
 // Uses go-i18n localizer for translations
-func formatWordDiff(userInput, correctWord string, localizer *i18n.Localizer) string {
+func formatWordDiff(userInput, correctWord string, localizer *i18n.Localizer, opts ...diffOption) string {
+	options := diffOptions{styles: defaultStyleSet()}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	
 	// Convert to rune slices to handle Unicode characters properly
 	// Runes are Go's representation of Unicode code points
 	userRunes := []rune(userInput)
@@ -60,11 +187,16 @@ func formatWordDiff(userInput, correctWord string, localizer *i18n.Localizer) st
 		maxLen = len(correctRunes)
 	}
 	
-	// Build the comparison strings with color coding
+	wrongStyle := options.styles.wrongChar
+	if options.severity == severityNearMiss {
+		wrongStyle = options.styles.nearMissChar
+	}
+	
+	// Build the comparison cells with color coding, one per character
 	// We'll show matching characters in green, differences in red
-	var userLine strings.Builder
-	var correctLine strings.Builder
-	var diffLine strings.Builder
+	userCells := make([]string, maxLen)
+	correctCells := make([]string, maxLen)
+	diffCells := make([]string, maxLen)
 	
 	// Iterate through each position up to the maximum length
 	for i := 0; i < maxLen; i++ {
@@ -93,44 +225,119 @@ func formatWordDiff(userInput, correctWord string, localizer *i18n.Localizer) st
 		// Add characters to lines with appropriate styling
 		if isMatch {
 			// Both characters match - show in green
-			userLine.WriteString(correctCharStyle.Render(string(userChar)))
-			correctLine.WriteString(correctCharStyle.Render(string(correctChar)))
+			userCells[i] = options.styles.correctChar.Render(string(userChar))
+			correctCells[i] = options.styles.correctChar.Render(string(correctChar))
 		} else {
-			// Characters differ - show in red
-			userLine.WriteString(wrongCharStyle.Render(string(userChar)))
-			correctLine.WriteString(wrongCharStyle.Render(string(correctChar)))
+			// Characters differ - show in red, or amber for a near miss
+			userCells[i] = wrongStyle.Render(string(userChar))
+			correctCells[i] = wrongStyle.Render(string(correctChar))
 		}
 		
 		// Mark differences with colored indicators
 		if !isMatch {
-			diffLine.WriteString(diffMarkerStyle.Render("^"))  // Mark difference in yellow
+			diffCells[i] = diffMarkerStyle.Render("^")  // Mark difference in yellow
 		} else {
-			diffLine.WriteString(" ")  // Match - no marker
+			diffCells[i] = " "  // Match - no marker
 		}
 	}
 	
+	// Post-pass: swapped neighbors get their own marker instead of two "^"
+	var swaps []int
+	if options.transpositions {
+		swaps = findTranspositions(userRunes, correctRunes)
+	}
+	for _, i := range swaps {
+		diffCells[i] = diffMarkerStyle.Render(swapMarker)
+		diffCells[i+1] = diffMarkerStyle.Render(swapMarker)
+	}
+	
 	// Format the output with colored labels
-	// Use fixed-width labels (14 chars) to ensure proper alignment
-	// This accounts for ANSI escape codes in colored text
+	// All labels are padded to the widest one so the lines stay aligned
 	// Get labels from i18n localizer
-	yourInputText, _ := localizer.Localize(&i18n.LocalizeConfig{MessageID: "YourInput"})
-	correctText, _ := localizer.Localize(&i18n.LocalizeConfig{MessageID: "CorrectLabel"})
-	diffText, _ := localizer.Localize(&i18n.LocalizeConfig{MessageID: "Differences"})
-	
-	labelWidth := 14
-	yourInputLabel := labelStyle.Width(labelWidth).Render(yourInputText)
-	correctLabel := labelStyle.Width(labelWidth).Render(correctText)
-	diffLabel := labelStyle.Width(labelWidth).Render(diffText)
-	
-	return fmt.Sprintf(
-		"%s  %s\n"+
-			"%s  %s\n"+
-			"%s  %s",
-		yourInputLabel,
-		userLine.String(),
-		correctLabel,
-		correctLine.String(),
-		diffLabel,
-		diffLine.String(),
-	)
+	yourInputText := tr(localizer, "YourInput")
+	correctText := tr(localizer, "CorrectLabel")
+	diffText := tr(localizer, "Differences")
+	
+	labelWidth := maxLabelWidth(yourInputText, correctText, diffText)
+	labels := []string{
+		labelStyle.Width(labelWidth).Render(yourInputText),
+		labelStyle.Width(labelWidth).Render(correctText),
+		labelStyle.Width(labelWidth).Render(diffText),
+	}
+	// Continuation blocks of wrapped phrases get blank labels, so the
+	// characters stay in the same columns as in the first block
+	blank := strings.Repeat(" ", labelWidth)
+	
+	// Long phrases are split into blocks that fit next to the labels
+	segmentWidth := maxLen
+	if options.maxWidth > 0 {
+		segmentWidth = options.maxWidth - labelWidth - 2
+		if segmentWidth < 1 {
+			segmentWidth = 1
+		}
+	}
+	
+	blockWidth := min(segmentWidth, maxLen)
+	var blocks []string
+	for start := 0; ; start += segmentWidth {
+		end := min(start+segmentWidth, maxLen)
+		rows := [][]string{userCells[start:end], correctCells[start:end], diffCells[start:end]}
+		
+		lines := make([]string, len(rows))
+		for r, cells := range rows {
+			label := labels[r]
+			if start > 0 {
+				label = blank
+			}
+			content := joinCells(cells, options.rtl)
+			if options.rtl {
+				// Right-to-left: the words start at the right edge, next to the
+				// labels, so a shorter last block is padded on the left
+				content = strings.Repeat(" ", blockWidth-len(cells)) + content
+				lines[r] = fmt.Sprintf("%s  %s", content, label)
+			} else {
+				lines[r] = fmt.Sprintf("%s  %s", label, content)
+			}
+		}
+		blocks = append(blocks, strings.Join(lines, "\n"))
+		
+		if end >= maxLen {
+			break
+		}
+	}
+	
+	diff := strings.Join(blocks, "\n\n")
+	if len(swaps) > 0 {
+		swapNote := tr(localizer, "SwapNote", map[string]interface{}{"Marker": swapMarker})
+		diff += "\n\n" + labelStyle.Render(swapNote)
+	}
+	return diff
+}
+
+// joinCells concatenates rendered characters, in reverse for right-to-left
+// scripts so the first character is drawn in the rightmost column
+func joinCells(cells []string, rtl bool) string {
+	var b strings.Builder
+	for k := range cells {
+		if rtl {
+			b.WriteString(cells[len(cells)-1-k])
+		} else {
+			b.WriteString(cells[k])
+		}
+	}
+	return b.String()
+}
+
+// maxLabelWidth returns the display width of the widest label
+// lipgloss.Width counts terminal cells rather than bytes, so umlauts count
+// once and wide runes (e.g., CJK characters) count twice
+// Translations of any length therefore line up without a magic constant
+func maxLabelWidth(labels ...string) int {
+	width := 0
+	for _, label := range labels {
+		if w := lipgloss.Width(label); w > width {
+			width = w
+		}
+	}
+	return width
 }
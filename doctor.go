@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// doctorCheck is one item of the --doctor checklist
+// run returns nil if everything is fine, or an error that explains what
+// is wrong (and ideally how to fix it)
+type doctorCheck struct {
+	name string
+	run  func() error
+}
+
+// getenv reads an environment variable
+// It is a variable so tests can simulate different terminals
+var getenv = os.Getenv
+
+// doctorChecks returns the checks of --doctor in the order they are shown
+func doctorChecks() []doctorCheck {
+	return []doctorCheck{
+		{"Text-to-speech", checkSpeech},
+		{"Terminal colors", checkColors},
+		{"Terminal Unicode", checkUnicode},
+		{"Translations", checkTranslations},
+		{"Sample config", checkSampleConfigs},
+	}
+}
+
+// runDoctor runs every check and prints a checklist with the result of each
+// All checks run even if one fails, so a single run shows every problem;
+// the returned error only says how many failed
+func runDoctor(w io.Writer) error {
+	checks := doctorChecks()
+	failed := 0
+	for _, check := range checks {
+		if err := check.run(); err != nil {
+			failed++
+			fmt.Fprintf(w, "%s %s: %v\n", errorStyle.Render("✘"), check.name, err)
+			continue
+		}
+		fmt.Fprintf(w, "%s %s\n", successStyle.Render("✔"), check.name)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(checks))
+	}
+	return nil
+}
+
+// checkSpeech finds a text-to-speech command and speaks a test phrase
+// Whether the phrase was actually heard can only be told by the user
+func checkSpeech() error {
+	speaker := detectTTSEngine()
+	if _, silent := speaker.(noneEngine); silent {
+		return fmt.Errorf("neither say nor espeak was found on your PATH; install one of them to hear the words")
+	}
+	if err := speaker.Speak("Dictation test", "en", defaultRate); err != nil {
+		return fmt.Errorf("speaking a test phrase failed: %w", err)
+	}
+	return nil
+}
+
+// checkColors reports terminals that can't show the colored feedback
+func checkColors() error {
+	if getenv("NO_COLOR") != "" {
+		return fmt.Errorf("NO_COLOR is set, so the feedback is shown without colors")
+	}
+	if term := getenv("TERM"); term == "" || term == "dumb" {
+		return fmt.Errorf("TERM is %q, which doesn't support colors", term)
+	}
+	return nil
+}
+
+// checkUnicode reports terminals whose locale can't show umlauts and emoji
+// The first locale variable that is set decides, like in the C library
+func checkUnicode() error {
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		locale := getenv(name)
+		if locale == "" {
+			continue
+		}
+		lower := strings.ToLower(locale)
+		if strings.Contains(lower, "utf-8") || strings.Contains(lower, "utf8") {
+			return nil
+		}
+		return fmt.Errorf("%s=%s is not a UTF-8 locale; umlauts and emoji may look garbled", name, locale)
+	}
+	return fmt.Errorf("no locale is set (LANG); set it to a UTF-8 locale like en_US.UTF-8")
+}
+
+// checkTranslations loads the translation files, which are read from the
+// working directory when the app starts
+func checkTranslations() error {
+	_, err := initI18n("en")
+	return err
+}
+
+// checkSampleConfigs loads each bundled example like a config file
+func checkSampleConfigs() error {
+	dir, err := os.MkdirTemp("", "dictation-doctor")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	for _, name := range exampleNames() {
+		path := filepath.Join(dir, name+".yaml")
+		file, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		err = writeExampleConfig(name, file)
+		file.Close()
+		if err != nil {
+			return err
+		}
+		if _, err := loadConfig(path); err != nil {
+			return fmt.Errorf("example %s: %w", name, err)
+		}
+	}
+	return nil
+}
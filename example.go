@@ -0,0 +1,39 @@
+package main
+
+import (
+	"embed"
+	"fmt"
+	"io"
+	"io/fs"
+	"sort"
+	"strings"
+)
+
+// exampleFiles holds the sample word lists bundled into the binary
+// New users can print one with --example and use it as a starting point
+//
+//go:embed examples/*.yaml
+var exampleFiles embed.FS
+
+// exampleNames returns the names of the bundled examples (without .yaml),
+// sorted alphabetically
+func exampleNames() []string {
+	// fs.Glob works on any file system, including the embedded one
+	paths, _ := fs.Glob(exampleFiles, "examples/*.yaml")
+	names := make([]string, 0, len(paths))
+	for _, path := range paths {
+		names = append(names, strings.TrimSuffix(strings.TrimPrefix(path, "examples/"), ".yaml"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// writeExampleConfig writes the bundled example config called name to w
+func writeExampleConfig(name string, w io.Writer) error {
+	data, err := exampleFiles.ReadFile("examples/" + name + ".yaml")
+	if err != nil {
+		return fmt.Errorf("unknown example %q (available: %s)", name, strings.Join(exampleNames(), ", "))
+	}
+	_, err = w.Write(data)
+	return err
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// favoritesPath returns the file with the words the learner bookmarked
+// It lives in the home directory, so the favorites are shared by every
+// word list and session
+func favoritesPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("no home directory for the favorites: %w", err)
+	}
+	return filepath.Join(home, ".dictation", "favorites.txt"), nil
+}
+
+// loadFavorites reads the favorite words, one per line
+// Without any favorites yet, the list is empty and there is no error
+func loadFavorites() ([]string, error) {
+	path, err := favoritesPath()
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read favorites: %w", err)
+	}
+	defer file.Close()
+
+	var words []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if word := strings.TrimSpace(scanner.Text()); word != "" {
+			words = append(words, word)
+		}
+	}
+	return words, scanner.Err()
+}
+
+// addFavorite appends a word to the favorites, creating the file and its
+// directory on first use; a word that is already a favorite is kept once
+func addFavorite(word string) error {
+	favorites, err := loadFavorites()
+	if err != nil {
+		return err
+	}
+	for _, favorite := range favorites {
+		if favorite == word {
+			return nil
+		}
+	}
+
+	path, err := favoritesPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create favorites directory: %w", err)
+	}
+	// O_APPEND adds to the end, O_CREATE creates the file if it is missing
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to save favorite: %w", err)
+	}
+	defer file.Close()
+	if _, err := fmt.Fprintln(file, word); err != nil {
+		return fmt.Errorf("failed to save favorite: %w", err)
+	}
+	return nil
+}
+
+// favoriteWords returns the words of the list that are favorites, in the
+// order they were added; favorites from other lists are left out
+func favoriteWords(config *Config, favorites []string) []string {
+	entries := config.entries()
+	var words []string
+	for _, word := range favorites {
+		if _, ok := entries[word]; ok {
+			words = append(words, word)
+		}
+	}
+	return words
+}
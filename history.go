@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+// HistoryEntry is one finished session in the history file
+// The history is a JSONL file: one JSON object per line, so each new
+// session is simply appended without rewriting the file
+// SessionResult is embedded, so its fields are part of the same object
+type HistoryEntry struct {
+	Time     time.Time `json:"time"`
+	Title    string    `json:"title,omitempty"`
+	Language string    `json:"language"`
+	SessionResult
+}
+
+// appendHistory adds a session to the history file, creating it if needed
+func appendHistory(path string, entry HistoryEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write history file: %w", err)
+	}
+	return nil
+}
+
+// MissedWord counts the wrong answers for a word across all sessions
+type MissedWord struct {
+	Word   string
+	Misses int
+}
+
+// HistorySummary aggregates all sessions of a history file
+type HistorySummary struct {
+	Sessions      int
+	TotalAttempts int
+	CorrectCount  int
+	Accuracy      int          // Percentage of correct attempts over all sessions
+	Trend         []int        // Accuracy of each session, oldest first
+	AverageWords  float64      // Average number of words per session
+	MostMissed    []MissedWord // Words with the most wrong answers, most first
+	Language      string       // Language of the most recent session
+}
+
+// maxMostMissed is the number of most-missed words in a summary
+const maxMostMissed = 5
+
+// summarizeHistory reads a JSONL history and computes the aggregates
+// Blank lines are skipped; a line that isn't valid JSON is an error
+func summarizeHistory(r io.Reader) (HistorySummary, error) {
+	var summary HistorySummary
+	misses := make(map[string]int)
+	totalWords := 0
+
+	scanner := bufio.NewScanner(r)
+	// Sessions with long lists make long lines, so allow up to 1 MiB
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var entry HistoryEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return HistorySummary{}, fmt.Errorf("invalid history entry on line %d: %w", line, err)
+		}
+
+		summary.Sessions++
+		summary.TotalAttempts += entry.TotalAttempts
+		summary.CorrectCount += entry.CorrectCount
+		summary.Trend = append(summary.Trend, entry.Accuracy)
+		summary.Language = entry.Language
+		totalWords += entry.WordCount
+
+		for _, word := range entry.Words {
+			if word.Misses > 0 {
+				misses[word.Word] += word.Misses
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return HistorySummary{}, err
+	}
+
+	if summary.TotalAttempts > 0 {
+		summary.Accuracy = summary.CorrectCount * 100 / summary.TotalAttempts
+	}
+	if summary.Sessions > 0 {
+		summary.AverageWords = float64(totalWords) / float64(summary.Sessions)
+	}
+
+	for word, count := range misses {
+		summary.MostMissed = append(summary.MostMissed, MissedWord{Word: word, Misses: count})
+	}
+	// Most misses first; words with the same count in alphabetical order,
+	// so the result doesn't depend on the random map order
+	sort.Slice(summary.MostMissed, func(i, j int) bool {
+		a, b := summary.MostMissed[i], summary.MostMissed[j]
+		if a.Misses != b.Misses {
+			return a.Misses > b.Misses
+		}
+		return a.Word < b.Word
+	})
+	if len(summary.MostMissed) > maxMostMissed {
+		summary.MostMissed = summary.MostMissed[:maxMostMissed]
+	}
+	return summary, nil
+}
+
+// loadHistorySummary summarizes the history file at path
+func loadHistorySummary(path string) (HistorySummary, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return HistorySummary{}, fmt.Errorf("failed to read history file: %w", err)
+	}
+	defer file.Close()
+	return summarizeHistory(file)
+}
+
+// maxTrendSessions is the number of recent sessions shown in the trend
+const maxTrendSessions = 10
+
+// printHistorySummary writes the statistics as a small table
+func printHistorySummary(summary HistorySummary, localizer *i18n.Localizer, out io.Writer) {
+	fmt.Fprintln(out, dialogTitleStyle.Render(tr(localizer, "StatsTitle")))
+	fmt.Fprintln(out)
+	if summary.Sessions == 0 {
+		fmt.Fprintln(out, tr(localizer, "StatsNoHistory"))
+		return
+	}
+
+	// Only the most recent sessions, so the trend fits on one line
+	trend := summary.Trend
+	if len(trend) > maxTrendSessions {
+		trend = trend[len(trend)-maxTrendSessions:]
+	}
+	percents := make([]string, len(trend))
+	for i, accuracy := range trend {
+		percents[i] = strconv.Itoa(accuracy) + "%"
+	}
+
+	overview := table.New().
+		Border(lipgloss.RoundedBorder()).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			if col == 0 {
+				return labelStyle.Padding(0, 1)
+			}
+			return lipgloss.NewStyle().Padding(0, 1)
+		}).
+		Row(tr(localizer, "StatsSessions"), strconv.Itoa(summary.Sessions)).
+		Row(tr(localizer, "StatsAccuracy"), strconv.Itoa(summary.Accuracy)+"%").
+		Row(tr(localizer, "StatsTrend"), strings.Join(percents, " → ")).
+		Row(tr(localizer, "StatsAverageWords"), strconv.FormatFloat(summary.AverageWords, 'f', 1, 64))
+	fmt.Fprintln(out, overview.Render())
+
+	if len(summary.MostMissed) == 0 {
+		return
+	}
+	missed := table.New().
+		Border(lipgloss.RoundedBorder()).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			if row == table.HeaderRow {
+				return labelStyle.Padding(0, 1)
+			}
+			return lipgloss.NewStyle().Padding(0, 1)
+		}).
+		Headers(tr(localizer, "StatsMostMissed"), tr(localizer, "StatsMisses"))
+	for _, word := range summary.MostMissed {
+		missed.Row(word.Word, strconv.Itoa(word.Misses))
+	}
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, missed.Render())
+}
@@ -2,6 +2,9 @@ package main
 
 import (
 	"fmt"
+	"log"
+	"path/filepath"
+	"sync"
 
 	"github.com/nicksnyder/go-i18n/v2/i18n"
 	"github.com/pelletier/go-toml/v2"
@@ -22,13 +25,32 @@ func initI18n(langCode string) (*i18n.Localizer, error) {
 	// Load translation files
 	// These files contain all user-facing strings for each language
 	// LoadMessageFile returns (*MessageFile, error)
+	// English is the fallback for everything else, so it must be there
 	_, err := bundle.LoadMessageFile("active.en.toml")
 	if err != nil {
 		return nil, fmt.Errorf("failed to load English translations: %w", err)
 	}
-	_, err = bundle.LoadMessageFile("active.de.toml")
+	
+	// Every other active.XX.toml next to it adds a language, so a new
+	// translation only needs its file
+	paths, err := filepath.Glob("active.*.toml")
 	if err != nil {
-		return nil, fmt.Errorf("failed to load German translations: %w", err)
+		return nil, err
+	}
+	for _, path := range paths {
+		if path == "active.en.toml" {
+			continue
+		}
+		if _, err := bundle.LoadMessageFile(path); err != nil {
+			return nil, fmt.Errorf("failed to load translations from %s: %w", path, err)
+		}
+	}
+	
+	// Without a file for the language, every message would be reported
+	// as missing; say so once and use English instead
+	if !hasTranslations(bundle, langCode) {
+		log.Printf("Warning: No translations for '%s', falling back to English", langCode)
+		langCode = "en"
 	}
 	
 	// Create localizer for the requested language
@@ -37,3 +59,90 @@ func initI18n(langCode string) (*i18n.Localizer, error) {
 	
 	return localizer, nil
 }
+
+// hasTranslations reports whether the bundle has messages for the language
+// Only the base language is compared, so "de-AT" uses the German file
+func hasTranslations(bundle *i18n.Bundle, langCode string) bool {
+	tag, err := language.Parse(langCode)
+	if err != nil {
+		return false
+	}
+	base, _ := tag.Base()
+	for _, available := range bundle.LanguageTags() {
+		if availableBase, _ := available.Base(); availableBase == base {
+			return true
+		}
+	}
+	return false
+}
+
+// reportedMissingKeys remembers which message IDs were already logged
+// so that a missing key rendered on every frame is reported only once
+// While the TUI holds the screen, the warnings are held back in
+// heldMissingKeys and printed by releaseMissingKeyWarnings afterwards
+var (
+	reportedMissingKeys   = make(map[string]bool)
+	reportedMissingKeysMu sync.Mutex
+	holdMissingKeys       bool
+	heldMissingKeys       []string
+)
+
+// tr returns the localized message for id, filling in optional template data
+// Unlike calling Localize directly, it never returns an empty string: a missing
+// key is logged once and rendered as a visible "[MessageID]" placeholder
+func tr(localizer *i18n.Localizer, id string, data ...map[string]interface{}) string {
+	config := &i18n.LocalizeConfig{MessageID: id}
+	if len(data) > 0 {
+		config.TemplateData = data[0]
+	}
+	
+	// Localize may return a fallback (English) message together with an
+	// error, so the message is only replaced when it is actually empty
+	msg, err := localizer.Localize(config)
+	if err != nil {
+		reportMissingKey(id, err)
+	}
+	if msg == "" {
+		return "[" + id + "]"
+	}
+	return msg
+}
+
+// reportMissingKey logs a translation problem the first time it occurs
+func reportMissingKey(id string, err error) {
+	reportedMissingKeysMu.Lock()
+	defer reportedMissingKeysMu.Unlock()
+	
+	if reportedMissingKeys[id] {
+		return
+	}
+	reportedMissingKeys[id] = true
+	warning := fmt.Sprintf("Warning: translation for %q: %v", id, err)
+	if holdMissingKeys {
+		heldMissingKeys = append(heldMissingKeys, warning)
+		return
+	}
+	log.Print(warning)
+}
+
+// holdMissingKeyWarnings collects translation warnings instead of logging
+// them, so they don't scramble the full-screen interface
+func holdMissingKeyWarnings() {
+	reportedMissingKeysMu.Lock()
+	defer reportedMissingKeysMu.Unlock()
+	
+	holdMissingKeys = true
+}
+
+// releaseMissingKeyWarnings logs the collected translation warnings and
+// logs new ones right away again
+func releaseMissingKeyWarnings() {
+	reportedMissingKeysMu.Lock()
+	defer reportedMissingKeysMu.Unlock()
+	
+	for _, warning := range heldMissingKeys {
+		log.Print(warning)
+	}
+	heldMissingKeys = nil
+	holdMissingKeys = false
+}
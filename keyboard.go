@@ -0,0 +1,50 @@
+package main
+
+// typingKeys lists, per operating system (as in runtime.GOOS), the keys
+// that type a special character on a US keyboard layout
+// Keys separated by a space are pressed one after the other:
+//   - macOS uses dead keys: Option+u, then the letter, makes an umlaut
+//   - Linux uses the Compose key, which has to be enabled in the settings
+//   - Windows uses Alt codes typed on the numeric keypad
+var typingKeys = map[string]map[rune]string{
+	"darwin": {
+		'ä': "⌥+u a", 'ö': "⌥+u o", 'ü': "⌥+u u",
+		'Ä': "⌥+u ⇧+a", 'Ö': "⌥+u ⇧+o", 'Ü': "⌥+u ⇧+u",
+		'ß': "⌥+s",
+		'é': "⌥+e e", 'è': "⌥+` e", 'ê': "⌥+i e", 'à': "⌥+` a",
+		'ç': "⌥+c", 'ñ': "⌥+n n",
+	},
+	"linux": {
+		'ä': "Compose \" a", 'ö': "Compose \" o", 'ü': "Compose \" u",
+		'Ä': "Compose \" A", 'Ö': "Compose \" O", 'Ü': "Compose \" U",
+		'ß': "Compose s s",
+		'é': "Compose ' e", 'è': "Compose ` e", 'ê': "Compose ^ e", 'à': "Compose ` a",
+		'ç': "Compose , c", 'ñ': "Compose ~ n",
+	},
+	"windows": {
+		'ä': "Alt+0228", 'ö': "Alt+0246", 'ü': "Alt+0252",
+		'Ä': "Alt+0196", 'Ö': "Alt+0214", 'Ü': "Alt+0220",
+		'ß': "Alt+0223",
+		'é': "Alt+0233", 'è': "Alt+0232", 'ê': "Alt+0234", 'à': "Alt+0224",
+		'ç': "Alt+0231", 'ñ': "Alt+0241",
+	},
+}
+
+// typingHintsFor returns how to type each special character of word on
+// the given operating system, e.g. "ü: ⌥+u u" on macOS
+// Every character is listed once, in the order it appears in the word;
+// words without special characters or unknown systems give no hints
+func typingHintsFor(word, os string) []string {
+	keys := typingKeys[os]
+	var hints []string
+	seen := make(map[rune]bool)
+	for _, r := range word {
+		sequence, ok := keys[r]
+		if !ok || seen[r] {
+			continue
+		}
+		seen[r] = true
+		hints = append(hints, string(r)+": "+sequence)
+	}
+	return hints
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// sessionLogger appends one line per submitted answer to a log file
+// The lines are tab-separated so they are easy to read and to process:
+//
+//	2026-10-16T09:30:00+02:00	Haus	Hau	incorrect	1
+//
+// A nil *sessionLogger is valid and logs nothing
+type sessionLogger struct {
+	w io.Writer
+}
+
+// openSessionLogger opens (or creates) the log file for appending
+// Earlier sessions stay in the file, so one log can cover many sessions
+func openSessionLogger(path string) (*sessionLogger, *os.File, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open log file: %w", err)
+	}
+	return &sessionLogger{w: file}, file, nil
+}
+
+// LogAttempt writes a line for one answer: time, word, typed input,
+// correct/incorrect and the number of the attempt for this word
+// Write errors are ignored because logging must not interrupt the practice
+func (l *sessionLogger) LogAttempt(word, input string, correct bool, attempt int) {
+	if l == nil {
+		return
+	}
+	result := "incorrect"
+	if correct {
+		result = "correct"
+	}
+	fmt.Fprintf(l.w, "%s\t%s\t%s\t%s\t%d\n", time.Now().Format(time.RFC3339), word, input, result, attempt)
+}
@@ -1,9 +1,14 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
+	"io/fs"
 	"log"
 	"os"
+	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -17,24 +22,178 @@ func main() {
 	// os.Args contains command-line arguments
 	// os.Args[0] is the program name, os.Args[1:] are arguments
 	
+	// Command-line flags are parsed with the standard flag package
+	// Flags must come before the config file arguments
+	showVersion := flag.Bool("version", false, "print version and exit")
+	flag.BoolVar(showVersion, "v", false, "print version and exit (shorthand)")
+	loops := flag.Int("loop", 1, "repeat the word list `N` times (0 repeats until you quit)")
+	exportDir := flag.String("export-audio", "", "write one audio file per word to `dir` and exit")
+	count := flag.Int("count", 0, "practice `N` randomly chosen words (0 = all)")
+	warmup := flag.Int("warmup", 0, "start with `N` random warm-up words that don't count")
+	engineName := flag.String("tts-engine", "", "text-to-speech `engine`: say, espeak or none (default: auto-detect)")
+	seed := flag.Int64("seed", 0, "seed for shuffling and sampling to get reproducible sessions (0 = random)")
+	singlePass := flag.Bool("single-pass", false, "present every word exactly once, without repeating misspelled words")
+	plain := flag.Bool("plain", false, "use a plain line-by-line prompt instead of the full-screen interface (for screen readers)")
+	pattern := flag.String("pattern", "", "only practice words matching the regular expression `regex` (e.g. sch)")
+	focus := flag.String("focus", "", "comma-separated `words` to practice first, before the shuffled rest")
+	logFile := flag.String("log", "", "append a timestamped line per answer to `file`")
+	preview := flag.Bool("preview", false, "read the whole list aloud once in order before practicing")
+	targetAccuracy := flag.Int("target-accuracy", 0, "end the session early once the accuracy reaches `percent` (0 = never)")
+	minWords := flag.Int("min-words", 10, "answers needed before --target-accuracy can end the session")
+	ramp := flag.Bool("ramp", false, "practice easy words first, then medium and hard ones (shuffled within each level)")
+	noShuffle := flag.Bool("no-shuffle", false, "practice the words in config order instead of shuffling them")
+	skipIntro := flag.Bool("skip-intro", false, "start with the first word without showing the intro screen")
+	jsonOutput := flag.Bool("json", false, "run without the TUI and print the session result as JSON (requires --answers)")
+	answersFile := flag.String("answers", "", "read answers from `file`, one per line (used with --json)")
+	historyFile := flag.String("history", "", "append a summary of every session to `file` (JSON lines)")
+	showStats := flag.Bool("stats", false, "print statistics from the --history file and exit")
+	sessionName := flag.String("session", "", "save the progress as session `name` on exit and resume it on the next run")
+	example := flag.String("example", "", "print the bundled example config `name` and exit (e.g. german-basics)")
+	doctor := flag.Bool("doctor", false, "check text-to-speech, terminal, translations and configs, then exit")
+	worksheet := flag.String("worksheet", "", "write a printable worksheet with an answer key to `file` and exit")
+	favoritesOnly := flag.Bool("favorites", false, "practice only the words you marked as favorites with Ctrl+F")
+	flag.Parse()
+	
 	// Check for version flag
-	if len(os.Args) > 1 && (os.Args[1] == "-v" || os.Args[1] == "--version" || os.Args[1] == "version") {
+	if *showVersion || flag.Arg(0) == "version" {
 		fmt.Printf("dictation version %s\n", Version)
 		os.Exit(0)
 	}
 	
+	// A checklist of the setup, to find out why there is no sound
+	if *doctor {
+		if err := runDoctor(os.Stdout); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		return
+	}
+	
+	// Print a sample config to start from, e.g. dictation --example german-basics > config.yaml
+	if *example != "" {
+		if err := writeExampleConfig(*example, os.Stdout); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		return
+	}
+	
+	// Statistics over all recorded sessions, without starting a new one
+	if *showStats {
+		if *historyFile == "" {
+			log.Fatalf("Error: --stats requires --history")
+		}
+		summary, err := loadHistorySummary(*historyFile)
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		// The statistics use the language of the most recent session
+		language := summary.Language
+		if language == "" {
+			language = "en"
+		}
+		localizer, err := initI18n(language)
+		if err != nil {
+			log.Fatalf("Error initializing i18n: %v", err)
+		}
+		printHistorySummary(summary, localizer, os.Stdout)
+		return
+	}
+	
 	// Default config file path
-	configFile := "config.yaml"
-	if len(os.Args) > 1 {
-		configFile = os.Args[1]  // Use first argument as config file
+	configFiles := []string{"config.yaml"}
+	if flag.NArg() > 0 {
+		configFiles = flag.Args()  // Use all remaining arguments as config files
 	}
 
 	// Load configuration - handle errors with log.Fatalf
 	// Fatalf prints error and exits program (os.Exit(1))
-	config, err := loadConfig(configFile)
+	// Several config files are merged into a single word list
+	config, err := loadConfigs(configFiles)
 	if err != nil {
 		log.Fatalf("Error loading config: %v", err)
 	}
+	if !config.Duplicates.empty() {
+		log.Printf("Warning: %s", config.Duplicates)
+	}
+	if *loops < 0 {
+		log.Fatalf("Error: --loop must not be negative")
+	}
+	config.Loops = *loops
+	if *skipIntro {
+		config.SkipIntro = true
+	}
+	if *singlePass {
+		config.SinglePass = true
+	}
+	config.NoShuffle = *noShuffle
+	config.Ramp = *ramp
+	if *targetAccuracy < 0 || *targetAccuracy > 100 {
+		log.Fatalf("Error: --target-accuracy must be between 0 and 100")
+	}
+	config.TargetAccuracy = *targetAccuracy
+	config.MinWords = *minWords
+	config.Preview = *preview
+	config.Plain = *plain
+	if *pattern != "" {
+		matching, err := filterWordsByPattern(config.wordList(), *pattern)
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		config.selectWords(matching)
+	}
+	if *favoritesOnly {
+		favorites, err := loadFavorites()
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		words := favoriteWords(config, favorites)
+		if len(words) == 0 {
+			log.Fatalf("Error: none of the words in the list is a favorite yet; press Ctrl+F during practice to add one")
+		}
+		config.selectWords(words)
+	}
+	if *focus != "" {
+		config.Focus = strings.Split(*focus, ",")
+		for i := range config.Focus {
+			config.Focus[i] = strings.TrimSpace(config.Focus[i])
+		}
+	}
+
+	// Select the text-to-speech engine
+	speaker, err := ttsEngine(*engineName)
+	if err != nil {
+		log.Fatalf("Error selecting TTS engine: %v", err)
+	}
+	// Configured voices only apply to say; espeak picks voices by language
+	// The output device, volume and pre-roll are options of say, too
+	if say, ok := speaker.(sayEngine); ok {
+		say.voices = config.Voices
+		say.volume = config.Volume
+		say.preRoll = config.PreRollSilence
+		if config.AudioDevice != "" {
+			if err := checkAudioDevice(config.AudioDevice); err != nil {
+				log.Printf("Warning: %v; using the default output", err)
+			} else {
+				say.device = config.AudioDevice
+			}
+		}
+		speaker = say
+	}
+	if _, silent := speaker.(noneEngine); silent && *engineName == "" {
+		// Auto-detection found nothing: keep going, but say why it is quiet
+		log.Printf("Warning: no text-to-speech command (say or espeak) found on your PATH; words will not be spoken")
+	}
+	// Export audio files instead of practicing
+	if *exportDir != "" {
+		exporter, ok := speaker.(audioExporter)
+		if !ok {
+			log.Fatalf("Error: --export-audio needs the say or espeak engine")
+		}
+		if err := exportAudio(config.Words, config.Language, *exportDir, exporter); err != nil {
+			log.Fatalf("Error exporting audio: %v", err)
+		}
+		fmt.Printf("Exported %d audio files to %s\n", len(config.Words), *exportDir)
+		return
+	}
 
 	// Initialize i18n with go-i18n library
 	// This loads translation files and creates a localizer
@@ -43,14 +202,200 @@ func main() {
 		log.Fatalf("Error initializing i18n: %v", err)
 	}
 
-	// Shuffle words for variety in practice sessions
-	words := shuffleWords(config.Words)
+	// JSON mode replays recorded answers without the TUI, for dashboards
+	// and scripts; the words are practiced in config order and not spoken
+	if *jsonOutput {
+		if *answersFile == "" {
+			log.Fatalf("Error: --json requires --answers")
+		}
+		if err := checkSessionSupport(config); err != nil {
+			log.Fatalf("Error: %v; it can't be used with --json", err)
+		}
+		answers, err := loadAnswers(*answersFile)
+		if err != nil {
+			log.Fatalf("Error loading answers: %v", err)
+		}
+		if err := writeJSONResult(config, localizer, noneEngine{}, answers, os.Stdout); err != nil {
+			log.Fatalf("Error running session: %v", err)
+		}
+		return
+	}
+
+	// A fixed seed makes the word order and sample reproducible
+	if *seed != 0 {
+		seedRandom(*seed)
+	}
+
+	// Shuffle words for variety in practice sessions (unless --no-shuffle)
+	// With --count, only a sample of the list is practiced, where words
+	// with a higher weight are more likely to be chosen
+	if *count > len(config.Words) {
+		log.Printf("Warning: --count %d exceeds the %d words in the list, using all words", *count, len(config.Words))
+	}
+	// Focus words move to the front before the sample is cut, so they are
+	// always practiced; the first n words of a weighted order are a
+	// weighted sample of n words
+	words := applyFocus(sessionWords(config, 0), config.Focus)
+	if *count > 0 && *count < len(words) {
+		words = words[:*count]
+	}
+	// The ramp orders the chosen words by difficulty; focus words are
+	// still practiced, but at the level of their difficulty
+	if config.Ramp {
+		words = reorderWords(config, words)
+	}
+
+	// A paper worksheet of the chosen words, in the same order, e.g. as a
+	// fallback for a lesson without computers
+	if *worksheet != "" {
+		config.selectWords(words)
+		file, err := os.Create(*worksheet)
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		err = generateWorksheet(file, config)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			log.Fatalf("Error writing worksheet: %v", err)
+		}
+		fmt.Printf("Wrote a worksheet with %d words to %s\n", len(words), *worksheet)
+		return
+	}
+
+	// Synthesize each word once and replay the recording on repeats;
+	// playing the file needs an audio player like afplay, which can't
+	// choose the output device, so a configured device skips the cache
+	// A random pick from several voices can't be replayed either
+	var cache *cachingSpeaker
+	if engine, ok := speaker.(synthesizer); ok && config.AudioDevice == "" && !config.hasVoiceChoice() {
+		if player := detectSoundPlayer(); player != "" {
+			cache, err = newCachingSpeaker(engine, player)
+			if err != nil {
+				log.Printf("Warning: %v; speaking without a cache", err)
+			} else {
+				speaker = cache
+			}
+		}
+	}
+	// log.Fatalf skips deferred calls, so the recordings are removed by
+	// hand as soon as the practice is over
+	closeCache := func() {
+		if cache != nil {
+			cache.Close()
+		}
+	}
+
+	// Plain mode: a line-by-line prompt on stdin/stdout for screen readers
+	if config.Plain {
+		if *sessionName != "" {
+			log.Printf("Warning: --session is not supported with --plain; the progress is not saved")
+		}
+		if err := checkSessionSupport(config); err != nil {
+			log.Fatalf("Error: %v; it can't be used with --plain", err)
+		}
+		config.selectWords(words)
+		result, err := RunSession(config, localizer, speaker, newLineAnswers(os.Stdin), os.Stdout)
+		closeCache()
+		if err != nil {
+			log.Fatalf("Error running session: %v", err)
+		}
+		fmt.Println()
+		printSummary(result, localizer, os.Stdout)
+		recordHistory(*historyFile, config, result)
+		return
+	}
 
 	// Create and run the TUI
-	model := initialAppModel(localizer, config.Language, words)
+	model := initialAppModel(localizer, config, words)
+	model.speaker = speaker
+	
+	// A named session continues where the last run with the name stopped
+	statePath := ""
+	resumed := false
+	if *sessionName != "" {
+		statePath, err = sessionStatePath(*sessionName)
+		if err != nil {
+			closeCache()
+			log.Fatalf("Error: %v", err)
+		}
+		state, err := loadSessionState(statePath)
+		if err == nil {
+			resumed = model.resumeSession(state)
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			log.Printf("Warning: %v; starting the session over", err)
+		}
+	}
+	if *warmup > 0 && !resumed {
+		model.startWarmup(orderWords(config.wordList(), *warmup, true))
+	}
+	
+	// A log file that can't be opened shouldn't stop the practice
+	if *logFile != "" {
+		logger, file, err := openSessionLogger(*logFile)
+		if err != nil {
+			log.Printf("Warning: %v; continuing without a log", err)
+		} else {
+			defer file.Close()
+			model.logger = logger
+		}
+	}
 	p := tea.NewProgram(model, tea.WithAltScreen())
 	
-	if _, err := p.Run(); err != nil {
+	// Translation warnings wait until the TUI has left the alt screen
+	holdMissingKeyWarnings()
+	final, err := p.Run()
+	releaseMissingKeyWarnings()
+	closeCache()
+	if err != nil {
 		log.Fatalf("Error running application: %v", err)
 	}
+	// Update returns the model as a value or a pointer, depending on the message
+	var finalModel appModel
+	switch model := final.(type) {
+	case appModel:
+		finalModel = model
+	case *appModel:
+		finalModel = *model
+	}
+	// A hard quit leaves no trace of the session
+	if finalModel.aborted {
+		return
+	}
+	recordHistory(*historyFile, config, finalModel.sessionResult())
+	if statePath != "" {
+		saveSession(statePath, finalModel)
+	}
+}
+
+// saveSession keeps the progress of an unfinished named session for the
+// next run; a finished session is removed, so the next run starts fresh
+func saveSession(path string, model appModel) {
+	if model.finished && !model.stoppedEarly {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			log.Printf("Warning: %v", err)
+		}
+		return
+	}
+	if err := saveSessionState(path, model.sessionState()); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+}
+
+// recordHistory appends the session to the history file, if one is set
+// Sessions without a single answer aren't worth recording
+func recordHistory(path string, config *Config, result SessionResult) {
+	if path == "" || result.TotalAttempts == 0 {
+		return
+	}
+	entry := HistoryEntry{
+		Time:          time.Now(),
+		Title:         config.Title,
+		Language:      config.Language,
+		SessionResult: result,
+	}
+	if err := appendHistory(path, entry); err != nil {
+		log.Printf("Warning: %v", err)
+	}
 }
@@ -1,10 +1,25 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/charmbracelet/lipgloss"
 	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"gopkg.in/yaml.v3"
 )
 
 // setupTestLocalizer creates a localizer for testing
@@ -315,3 +330,2088 @@ func TestFormatWordDiffSpecificCases(t *testing.T) {
 		}
 	})
 }
+
+// testEntries turns plain words into word entries
+func testEntries(words ...string) []WordEntry {
+	entries := make([]WordEntry, len(words))
+	for i, word := range words {
+		entries[i] = WordEntry{Word: word}
+	}
+	return entries
+}
+
+// writeTestConfig writes YAML content to a temporary config file and returns its path
+func writeTestConfig(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test config: %v", err)
+	}
+	return path
+}
+
+// TestLoadConfigShowDiff tests the show_diff default and override
+func TestLoadConfigShowDiff(t *testing.T) {
+	config, err := loadConfig(writeTestConfig(t, "config.yaml", "words: [Haus]\n"))
+	if err != nil {
+		t.Fatalf("loadConfig() error = %v", err)
+	}
+	if !config.ShowDiff {
+		t.Error("ShowDiff should default to true")
+	}
+
+	config, err = loadConfig(writeTestConfig(t, "config.yaml", "show_diff: false\nwords: [Haus]\n"))
+	if err != nil {
+		t.Fatalf("loadConfig() error = %v", err)
+	}
+	if config.ShowDiff {
+		t.Error("ShowDiff should be false when disabled in config")
+	}
+}
+
+// TestLoadConfigsMerge tests merging several config files with overlapping words
+func TestLoadConfigsMerge(t *testing.T) {
+	first := writeTestConfig(t, "animals.yaml", "title: Animals\nlanguage: de\nwords: [Hund, Katze, Maus]\n")
+	second := writeTestConfig(t, "house.yaml", "words: [Haus, Maus, Tür]\n")
+
+	config, err := loadConfigs([]string{first, second})
+	if err != nil {
+		t.Fatalf("loadConfigs() error = %v", err)
+	}
+
+	want := []string{"Hund", "Katze", "Maus", "Haus", "Tür"}
+	if strings.Join(config.wordList(), ",") != strings.Join(want, ",") {
+		t.Errorf("loadConfigs() words = %v, want %v", config.wordList(), want)
+	}
+	if config.Language != "de" {
+		t.Errorf("loadConfigs() language = %q, want %q", config.Language, "de")
+	}
+	if config.Title != "Animals" {
+		t.Errorf("loadConfigs() title = %q, want title of first file", config.Title)
+	}
+}
+
+// TestLoadConfigsConflictingLanguage tests that differing languages are rejected
+func TestLoadConfigsConflictingLanguage(t *testing.T) {
+	german := writeTestConfig(t, "de.yaml", "language: de\nwords: [Haus]\n")
+	english := writeTestConfig(t, "en.yaml", "language: en\nwords: [house]\n")
+
+	if _, err := loadConfigs([]string{german, english}); err == nil {
+		t.Error("loadConfigs() should fail for conflicting languages")
+	}
+}
+
+// TestLoadConfigThemeValidation tests that invalid theme colors are rejected
+func TestLoadConfigThemeValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		theme   string
+		wantErr bool
+	}{
+		{"ansi colors", "theme:\n  correct: \"2\"\n  wrong: \"196\"\n", false},
+		{"hex colors", "theme:\n  placeholder: \"#888\"\n  wrong: \"#ff0000\"\n", false},
+		{"color name", "theme:\n  wrong: red\n", true},
+		{"out of range", "theme:\n  correct: \"300\"\n", true},
+		{"bad hex", "theme:\n  correct: \"#12345\"\n", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeTestConfig(t, "config.yaml", tt.theme+"words: [Haus]\n")
+			_, err := loadConfig(path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("loadConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// recordingExporter is a fake audioExporter that records requested files
+type recordingExporter struct {
+	paths []string
+	texts []string
+	langs []string
+}
+
+func (r *recordingExporter) SpeakToFile(text, langCode, path string) error {
+	r.texts = append(r.texts, text)
+	r.langs = append(r.langs, langCode)
+	r.paths = append(r.paths, path)
+	return nil
+}
+
+func (r *recordingExporter) FileExtension() string {
+	return ".aiff"
+}
+
+// TestExportAudio tests that one file per word is requested in the target directory
+func TestExportAudio(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "audio")
+	exporter := &recordingExporter{}
+
+	words := []WordEntry{
+		{Word: "Haus", Article: "das"},
+		{Word: "guten Tag"},
+		{Word: "and/or", Language: "en"},
+		{Word: "haus"},
+		{Word: "Café", Pronunciation: "Kaffee"},
+	}
+	err := exportAudio(words, "de", dir, exporter)
+	if err != nil {
+		t.Fatalf("exportAudio() error = %v", err)
+	}
+
+	if _, err := os.Stat(dir); err != nil {
+		t.Errorf("exportAudio() should create the directory: %v", err)
+	}
+
+	// "haus" would overwrite "Haus" on case-insensitive file systems
+	want := []string{"Haus.aiff", "guten_Tag.aiff", "and_or.aiff", "haus_2.aiff", "Café.aiff"}
+	if len(exporter.paths) != len(want) {
+		t.Fatalf("exportAudio() wrote %d files, want %d", len(exporter.paths), len(want))
+	}
+	for i, path := range exporter.paths {
+		if filepath.Dir(path) != dir {
+			t.Errorf("file %q should be inside %q", path, dir)
+		}
+		if filepath.Base(path) != want[i] {
+			t.Errorf("file name = %q, want %q", filepath.Base(path), want[i])
+		}
+	}
+	// The words are spoken like in practice, in their own language
+	if got := strings.Join(exporter.texts, ","); got != "das Haus,guten Tag,and/or,haus,Kaffee" {
+		t.Errorf("exported texts = %s, want the spoken text of each word", got)
+	}
+	if got := strings.Join(exporter.langs, ","); got != "de,de,en,de,de" {
+		t.Errorf("exported languages = %s, want de,de,en,de,de", got)
+	}
+}
+
+// TestEspeakExport tests that espeak writes WAV files
+func TestEspeakExport(t *testing.T) {
+	calls := stubRunCommand(t)
+	var exporter audioExporter = espeakEngine{}
+	if exporter.FileExtension() != ".wav" {
+		t.Errorf("espeak extension = %q, want .wav", exporter.FileExtension())
+	}
+	if err := exporter.SpeakToFile("Haus", "de", "Haus.wav"); err != nil {
+		t.Fatalf("SpeakToFile() error = %v", err)
+	}
+	if got := strings.Join((*calls)[0], " "); got != "espeak -v de -s 180 -w Haus.wav Haus" {
+		t.Errorf("espeak call = %q", got)
+	}
+}
+
+// TestAudioFileName tests file name sanitization
+func TestAudioFileName(t *testing.T) {
+	tests := map[string]string{
+		"Straße":     "Straße",
+		"a b":        "a_b",
+		"../etc":     "_etc",
+		"what?":      "what_",
+		"..":         "word",
+		`back\slash`: "back_slash",
+	}
+	for word, want := range tests {
+		if got := audioFileName(word); got != want {
+			t.Errorf("audioFileName(%q) = %q, want %q", word, got, want)
+		}
+	}
+}
+
+// TestStripDiacritics tests removal of German umlauts and French accents
+func TestStripDiacritics(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"Höse", "Hose"},
+		{"Mädchen", "Madchen"},
+		{"Übung", "Ubung"},
+		{"café", "cafe"},
+		{"élève", "eleve"},
+		{"garçon", "garcon"},
+		{"Noël", "Noel"},
+		{"Straße", "Straße"}, // ß is a letter, not a diacritic
+		{"Haus", "Haus"},
+	}
+
+	for _, tt := range tests {
+		if got := stripDiacritics(tt.input); got != tt.want {
+			t.Errorf("stripDiacritics(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+// TestAnswerMatchesDiacriticsOptional tests the comparison with and without the flag
+func TestAnswerMatchesDiacriticsOptional(t *testing.T) {
+	strict := defaultConfig()
+	lenient := defaultConfig()
+	lenient.DiacriticsOptional = true
+
+	tests := []struct {
+		input, target string
+		wantStrict    bool
+		wantLenient   bool
+	}{
+		{"Mädchen", "Mädchen", true, true},
+		{"Madchen", "Mädchen", false, true},
+		{"Schon", "Schön", false, true},
+		{"cafe", "café", false, true},
+		{"eleve", "élève", false, true},
+		{"Mutter", "Müller", false, false},
+		{"madchen", "Mädchen", false, false}, // Case still matters
+	}
+
+	for _, tt := range tests {
+		if got := answerMatches(tt.input, tt.target, strict.Language, &strict); got != tt.wantStrict {
+			t.Errorf("strict answerMatches(%q, %q) = %v, want %v", tt.input, tt.target, got, tt.wantStrict)
+		}
+		if got := answerMatches(tt.input, tt.target, lenient.Language, &lenient); got != tt.wantLenient {
+			t.Errorf("lenient answerMatches(%q, %q) = %v, want %v", tt.input, tt.target, got, tt.wantLenient)
+		}
+	}
+}
+
+// TestSampleWords tests sampling a subset of the word list
+func TestSampleWords(t *testing.T) {
+	words := []string{"Haus", "Buch", "Schule", "Freund", "Wasser", "Apfel"}
+
+	sample := sampleWords(words, 3)
+	if len(sample) != 3 {
+		t.Fatalf("sampleWords() returned %d words, want 3", len(sample))
+	}
+
+	// Every sampled word must come from the list, without repetition
+	seen := make(map[string]bool)
+	for _, word := range sample {
+		if seen[word] {
+			t.Errorf("sampleWords() returned %q twice", word)
+		}
+		seen[word] = true
+		if !strings.Contains(strings.Join(words, ","), word) {
+			t.Errorf("sampleWords() returned unknown word %q", word)
+		}
+	}
+
+	// Asking for more words than available returns all of them
+	if got := sampleWords(words, 10); len(got) != len(words) {
+		t.Errorf("sampleWords(10) returned %d words, want %d", len(got), len(words))
+	}
+}
+
+// TestSampleWordsSeeded tests that a fixed seed gives reproducible samples
+func TestSampleWordsSeeded(t *testing.T) {
+	words := []string{"Haus", "Buch", "Schule", "Freund", "Wasser", "Apfel"}
+
+	seedRandom(42)
+	first := sampleWords(words, 3)
+	seedRandom(42)
+	second := sampleWords(words, 3)
+
+	if strings.Join(first, ",") != strings.Join(second, ",") {
+		t.Errorf("seeded samples differ: %v vs %v", first, second)
+	}
+}
+
+// TestScrambleLetters tests that a scramble is a permutation of the letters
+func TestScrambleLetters(t *testing.T) {
+	sorted := func(s string) string {
+		letters := []rune(s)
+		sort.Slice(letters, func(i, j int) bool { return letters[i] < letters[j] })
+		return string(letters)
+	}
+	for _, word := range []string{"Haus", "Straße", "ab", "Guten Morgen"} {
+		for seed := int64(1); seed <= 20; seed++ {
+			scrambled := scrambleLetters(word, seed)
+			if sorted(scrambled) != sorted(word) {
+				t.Errorf("scrambleLetters(%q, %d) = %q, not a permutation", word, seed, scrambled)
+			}
+			if scrambled == word {
+				t.Errorf("scrambleLetters(%q, %d) should change the order", word, seed)
+			}
+		}
+	}
+
+	// Spaces stay where they are, and the same seed gives the same scramble
+	if scrambled := scrambleLetters("Guten Morgen", 7); strings.Index(scrambled, " ") != 5 {
+		t.Errorf("space should stay in place, got %q", scrambled)
+	}
+	if scrambleLetters("Schule", 3) != scrambleLetters("Schule", 3) {
+		t.Error("same seed should give the same scramble")
+	}
+	if got := scrambleLetters("aa", 1); got != "aa" {
+		t.Errorf("scrambleLetters(aa) = %q, want aa", got)
+	}
+}
+
+// stubRunCommand replaces runCommand for the duration of a test and records calls
+func stubRunCommand(t *testing.T) *[][]string {
+	t.Helper()
+	var calls [][]string
+	original := runCommand
+	runCommand = func(name string, args ...string) error {
+		calls = append(calls, append([]string{name}, args...))
+		return nil
+	}
+	t.Cleanup(func() { runCommand = original })
+	return &calls
+}
+
+// stubLookPath pretends that only the given commands are installed
+func stubLookPath(t *testing.T, installed ...string) {
+	t.Helper()
+	original := lookPath
+	lookPath = func(file string) (string, error) {
+		for _, name := range installed {
+			if file == name {
+				return "/usr/bin/" + name, nil
+			}
+		}
+		return "", os.ErrNotExist
+	}
+	t.Cleanup(func() { lookPath = original })
+}
+
+// TestNoneEngineIsSilent tests that the none engine never runs an external command
+func TestNoneEngineIsSilent(t *testing.T) {
+	calls := stubRunCommand(t)
+
+	speaker, err := ttsEngine("none")
+	if err != nil {
+		t.Fatalf("ttsEngine(none) error = %v", err)
+	}
+	if err := speaker.Speak("Haus", "de", defaultRate); err != nil {
+		t.Errorf("none engine Speak() error = %v, want nil", err)
+	}
+	if len(*calls) != 0 {
+		t.Errorf("none engine ran external commands: %v", *calls)
+	}
+}
+
+// TestTTSEngineSelection tests the engine factory
+func TestTTSEngineSelection(t *testing.T) {
+	calls := stubRunCommand(t)
+	stubLookPath(t, "espeak")
+
+	speaker, err := ttsEngine("espeak")
+	if err != nil {
+		t.Fatalf("ttsEngine(espeak) error = %v", err)
+	}
+	_ = speaker.Speak("Haus", "de", 150)
+
+	want := "espeak -v de -s 150 Haus"
+	if len(*calls) != 1 || strings.Join((*calls)[0], " ") != want {
+		t.Errorf("espeak command = %v, want %q", *calls, want)
+	}
+
+	if _, err := ttsEngine("festival"); err == nil {
+		t.Error("ttsEngine() should reject unknown engines")
+	}
+}
+
+// TestSayAudioDevice tests the output device, volume and pre-roll options
+// of say
+func TestSayAudioDevice(t *testing.T) {
+	calls := stubRunCommand(t)
+
+	engine := sayEngine{device: "71", volume: 0.5}
+	_ = engine.Speak("Haus", "de", defaultRate)
+	want := "say -a 71 -v Anna -r 180 [[volm 0.5]] Haus"
+	if len(*calls) != 1 || strings.Join((*calls)[0], " ") != want {
+		t.Errorf("say command = %v, want %q", *calls, want)
+	}
+
+	// Without a device and volume, the command stays as before
+	*calls = nil
+	_ = sayEngine{}.Speak("Haus", "de", defaultRate)
+	if want := "say -v Anna -r 180 Haus"; strings.Join((*calls)[0], " ") != want {
+		t.Errorf("say command = %v, want %q", *calls, want)
+	}
+
+	// The pre-roll silence comes before the word, after the volume
+	*calls = nil
+	_ = sayEngine{volume: 0.5, preRoll: true}.Speak("Haus", "de", defaultRate)
+	if want := "say -v Anna -r 180 [[volm 0.5]] [[slnc 250]] Haus"; strings.Join((*calls)[0], " ") != want {
+		t.Errorf("say command = %v, want %q", *calls, want)
+	}
+
+	// An unknown device is reported, so the default output can be used
+	runCommand = func(name string, args ...string) error {
+		return errors.New("exit status 1")
+	}
+	if err := checkAudioDevice("nope"); err == nil || !strings.Contains(err.Error(), `"nope"`) {
+		t.Errorf("checkAudioDevice() error = %v, want an error naming the device", err)
+	}
+}
+
+// stubGetenv replaces the environment seen by getenv for the duration of a test
+func stubGetenv(t *testing.T, env map[string]string) {
+	t.Helper()
+	original := getenv
+	getenv = func(key string) string { return env[key] }
+	t.Cleanup(func() { getenv = original })
+}
+
+// TestRunDoctor tests the setup checklist of --doctor
+func TestRunDoctor(t *testing.T) {
+	calls := stubRunCommand(t)
+	stubLookPath(t, "espeak")
+	stubGetenv(t, map[string]string{"TERM": "xterm-256color", "LANG": "de_DE.UTF-8"})
+
+	var out bytes.Buffer
+	if err := runDoctor(&out); err != nil {
+		t.Fatalf("runDoctor() error = %v\n%s", err, out.String())
+	}
+	if len(*calls) != 1 || (*calls)[0][0] != "espeak" {
+		t.Errorf("should speak a test phrase with espeak, got %v", *calls)
+	}
+	for _, check := range doctorChecks() {
+		if !strings.Contains(out.String(), "✔ "+check.name) {
+			t.Errorf("checklist should pass %q, got:\n%s", check.name, out.String())
+		}
+	}
+
+	// Every check runs, even after the first failure
+	stubLookPath(t)
+	stubGetenv(t, map[string]string{"TERM": "dumb", "LC_ALL": "C", "LANG": "en_US.UTF-8"})
+	out.Reset()
+	err := runDoctor(&out)
+	if err == nil || err.Error() != "3 of 5 checks failed" {
+		t.Errorf("runDoctor() error = %v, want 3 failed checks", err)
+	}
+	for _, line := range []string{"✘ Text-to-speech", "✘ Terminal colors", "✘ Terminal Unicode: LC_ALL=C", "✔ Translations", "✔ Sample config"} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("checklist should contain %q, got:\n%s", line, out.String())
+		}
+	}
+}
+
+// TestFormatWordDiffRTL tests the right-to-left diff layout
+func TestFormatWordDiffRTL(t *testing.T) {
+	localizer := setupTestLocalizer()
+	if localizer == nil {
+		t.Fatal("Failed to set up test localizer")
+	}
+
+	// "שלום" (shalom) with the last letter missing
+	result := formatWordDiff("שלו", "שלום", localizer, withRTL(true))
+	lines := strings.Split(result, "\n")
+	if len(lines) != 3 {
+		t.Fatalf("formatWordDiff() should return 3 lines, got %d", len(lines))
+	}
+
+	// Characters are laid out right to left, so the word appears reversed
+	if !strings.Contains(lines[1], "םולש") {
+		t.Errorf("Correct line should be laid out right to left, got %q", lines[1])
+	}
+	// Missing letter is the leftmost column, padded in the input line
+	if !strings.HasPrefix(lines[0], " ולש") {
+		t.Errorf("Input line should be padded on the left, got %q", lines[0])
+	}
+	// The difference marker is on the left, labels on the right
+	if !strings.HasPrefix(lines[2], "^") {
+		t.Errorf("Difference marker should be leftmost, got %q", lines[2])
+	}
+	if !strings.Contains(strings.TrimSpace(lines[1]), "םולש  Correct:") {
+		t.Errorf("Labels should follow the words on the right, got %q", lines[1])
+	}
+}
+
+// TestConfigIsRTL tests deriving the writing direction from the language
+func TestConfigIsRTL(t *testing.T) {
+	tests := []struct {
+		config Config
+		want   bool
+	}{
+		{Config{Language: "he"}, true},
+		{Config{Language: "ar"}, true},
+		{Config{Language: "de"}, false},
+		{Config{Language: "en", RTL: true}, true},
+	}
+	for _, tt := range tests {
+		if got := tt.config.isRTL(); got != tt.want {
+			t.Errorf("isRTL() for %+v = %v, want %v", tt.config, got, tt.want)
+		}
+	}
+}
+
+// TestRunSession drives a full session with scripted answers and a silent speaker
+func TestRunSession(t *testing.T) {
+	config := defaultConfig()
+	config.Language = "en"
+	config.Words = testEntries("Haus", "Buch")
+
+	// Haus is misspelled once and comes back at the end of the queue
+	answers := &scriptedAnswers{answers: []string{"Hau", "Buch", "", "Haus"}}
+	var out strings.Builder
+
+	result, err := RunSession(&config, setupTestLocalizer(), noneEngine{}, answers, &out)
+	if err != nil {
+		t.Fatalf("RunSession() error = %v", err)
+	}
+
+	if !result.Completed {
+		t.Error("Session should be completed")
+	}
+	if result.TotalAttempts != 3 || result.CorrectCount != 2 {
+		t.Errorf("attempts = %d, correct = %d, want 3 and 2", result.TotalAttempts, result.CorrectCount)
+	}
+	if result.Accuracy != 66 {
+		t.Errorf("Accuracy = %d, want 66", result.Accuracy)
+	}
+	if len(result.Words) != 2 {
+		t.Fatalf("Words should contain one result per word, got %d", len(result.Words))
+	}
+
+	haus := result.Words[0]
+	if haus.Word != "Haus" || haus.Attempts != 2 || haus.Misses != 1 || !haus.Correct {
+		t.Errorf("Haus result = %+v, want 2 attempts, 1 miss and correct", haus)
+	}
+	if strings.Join(haus.Answers, ",") != "Hau,Haus" {
+		t.Errorf("Haus answers = %v, want [Hau Haus]", haus.Answers)
+	}
+
+	output := out.String()
+	for _, want := range []string{"Word 1: Type what you heard", "Incorrect spelling", "Differences:", "please enter a word"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Output should contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
+// TestCheckSessionSupport tests that options RunSession doesn't implement
+// are rejected instead of being ignored
+func TestCheckSessionSupport(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*Config)
+		wantErr bool
+	}{
+		{"dictation", func(c *Config) {}, false},
+		{"memory", func(c *Config) { c.Mode = modeMemory }, false},
+		{"choose", func(c *Config) { c.Mode = modeChoose }, true},
+		{"unscramble", func(c *Config) { c.Mode = modeUnscramble }, true},
+		{"copy", func(c *Config) { c.Mode = modeCopy }, true},
+		{"recap", func(c *Config) { c.RecapMissed = true }, true},
+		{"mastery", func(c *Config) { c.MasteryLoop = true }, true},
+		{"time limit", func(c *Config) { c.TimeLimit = 10 * time.Second }, true},
+		{"target accuracy", func(c *Config) { c.TargetAccuracy = 90 }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := defaultConfig()
+			tt.modify(&config)
+			if err := checkSessionSupport(&config); (err != nil) != tt.wantErr {
+				t.Errorf("checkSessionSupport() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestRunSessionMemoryMode tests that memory mode shows the word instead
+// of speaking it
+func TestRunSessionMemoryMode(t *testing.T) {
+	config := defaultConfig()
+	config.Language = "en"
+	config.Mode = modeMemory
+	config.Words = testEntries("Haus")
+
+	speaker := &recordingSpeaker{}
+	var out strings.Builder
+	if _, err := RunSession(&config, setupTestLocalizer(), speaker, &scriptedAnswers{answers: []string{"Haus"}}, &out); err != nil {
+		t.Fatalf("RunSession() error = %v", err)
+	}
+	if len(speaker.texts) != 0 {
+		t.Errorf("memory mode should not speak the word, spoke %v", speaker.texts)
+	}
+	if !strings.Contains(out.String(), "Memorize this word Haus") || !strings.Contains(out.String(), "Type the word from memory") {
+		t.Errorf("memory mode should show the word and ask for it, got:\n%s", out.String())
+	}
+}
+
+// TestRunSessionEndsEarly tests that running out of answers returns partial results
+func TestRunSessionEndsEarly(t *testing.T) {
+	config := defaultConfig()
+	config.Language = "en"
+	config.Words = testEntries("Haus", "Buch")
+
+	speaker := &recordingSpeaker{}
+	result, err := RunSession(&config, setupTestLocalizer(), speaker, &scriptedAnswers{answers: []string{"Haus"}}, io.Discard)
+	if err != nil {
+		t.Fatalf("RunSession() error = %v", err)
+	}
+
+	if result.Completed {
+		t.Error("Session should not be completed when answers run out")
+	}
+	if result.CorrectCount != 1 || result.Accuracy != 100 {
+		t.Errorf("correct = %d, accuracy = %d, want 1 and 100", result.CorrectCount, result.Accuracy)
+	}
+	if strings.Join(speaker.texts, ",") != "Haus,Buch" {
+		t.Errorf("speaker should have spoken both words, got %v", speaker.texts)
+	}
+}
+
+// TestLoadConfigWordEntries tests plain words and entries with metadata in one list
+func TestLoadConfigWordEntries(t *testing.T) {
+	path := writeTestConfig(t, "config.yaml", `language: de
+words:
+  - Buch
+  - word: Haus
+    article: das
+  - 42
+`)
+	config, err := loadConfig(path)
+	if err != nil {
+		t.Fatalf("loadConfig() error = %v", err)
+	}
+
+	want := []WordEntry{{Word: "Buch"}, {Word: "Haus", Article: "das"}, {Word: "42"}}
+	if len(config.Words) != len(want) {
+		t.Fatalf("loadConfig() words = %+v, want %+v", config.Words, want)
+	}
+	for i := range want {
+		if !reflect.DeepEqual(config.Words[i], want[i]) {
+			t.Errorf("word %d = %+v, want %+v", i, config.Words[i], want[i])
+		}
+	}
+}
+
+// TestWordEntryArticle tests spoken text and validation target with articles
+func TestWordEntryArticle(t *testing.T) {
+	entry := WordEntry{Word: "Haus", Article: "das"}
+
+	if got := entry.spokenText(); got != "das Haus" {
+		t.Errorf("spokenText() = %q, want %q", got, "das Haus")
+	}
+	if got := entry.target(false); got != "Haus" {
+		t.Errorf("target(false) = %q, want %q", got, "Haus")
+	}
+	if got := entry.target(true); got != "das Haus" {
+		t.Errorf("target(true) = %q, want %q", got, "das Haus")
+	}
+
+	// Words without an article are unaffected by RequireArticle
+	plain := WordEntry{Word: "laufen"}
+	if plain.spokenText() != "laufen" || plain.target(true) != "laufen" {
+		t.Error("Entries without article should speak and expect the plain word")
+	}
+}
+
+// TestTrMissingKey tests that unknown message IDs render a visible placeholder
+func TestTrMissingKey(t *testing.T) {
+	localizer := setupTestLocalizer()
+	if localizer == nil {
+		t.Fatal("Failed to set up test localizer")
+	}
+
+	if got := tr(localizer, "NoSuchMessage"); got != "[NoSuchMessage]" {
+		t.Errorf("tr() for unknown ID = %q, want %q", got, "[NoSuchMessage]")
+	}
+
+	got := tr(localizer, "WordPrompt", map[string]interface{}{"Number": 3})
+	if got != "Word 3: Type what you heard" {
+		t.Errorf("tr() = %q, want the localized message", got)
+	}
+}
+
+// TestHoldMissingKeyWarnings tests that translation warnings are logged
+// only after the TUI released them
+func TestHoldMissingKeyWarnings(t *testing.T) {
+	var logged bytes.Buffer
+	log.SetOutput(&logged)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	localizer := setupTestLocalizer()
+	holdMissingKeyWarnings()
+	tr(localizer, "HeldMessage")
+	if logged.Len() != 0 {
+		t.Errorf("warning logged while held: %q", logged.String())
+	}
+
+	releaseMissingKeyWarnings()
+	if !strings.Contains(logged.String(), "HeldMessage") {
+		t.Errorf("released warnings = %q, want the HeldMessage warning", logged.String())
+	}
+}
+
+// TestRunSessionImmediateRetry tests immediate retries without the TUI
+func TestRunSessionImmediateRetry(t *testing.T) {
+	config := defaultConfig()
+	config.Language = "en"
+	config.Words = testEntries("Haus", "Buch")
+	config.RetryMode = retryImmediate
+
+	speaker := &recordingSpeaker{}
+	answers := &scriptedAnswers{answers: []string{"Hau", "Haus", "Buch"}}
+	if _, err := RunSession(&config, setupTestLocalizer(), speaker, answers, io.Discard); err != nil {
+		t.Fatalf("RunSession() error = %v", err)
+	}
+
+	if got := strings.Join(speaker.texts, ","); got != "Haus,Haus,Buch" {
+		t.Errorf("spoken words = %s, want Haus,Haus,Buch", got)
+	}
+}
+
+// TestLoadConfigRetryMode tests validation of the retry mode
+func TestLoadConfigRetryMode(t *testing.T) {
+	if _, err := loadConfig(writeTestConfig(t, "config.yaml", "retry_mode: immediate\nwords: [Haus]\n")); err != nil {
+		t.Errorf("loadConfig() error = %v for immediate mode", err)
+	}
+	if _, err := loadConfig(writeTestConfig(t, "config.yaml", "retry_mode: sometimes\nwords: [Haus]\n")); err == nil {
+		t.Error("loadConfig() should reject unknown retry modes")
+	}
+}
+
+// TestPlaySound tests that the embedded sound is played with the available player
+func TestPlaySound(t *testing.T) {
+	calls := stubRunCommand(t)
+	stubLookPath(t, "aplay")
+
+	if err := playSound(soundCorrect); err != nil {
+		t.Fatalf("playSound() error = %v", err)
+	}
+	if len(*calls) != 1 {
+		t.Fatalf("playSound() ran %d commands, want 1", len(*calls))
+	}
+	call := (*calls)[0]
+	if call[0] != "aplay" || !strings.HasSuffix(call[len(call)-1], soundCorrect+".wav") {
+		t.Errorf("playSound() ran %v, want aplay with %s.wav", call, soundCorrect)
+	}
+
+	// Without any player, playing a sound is a silent no-op
+	stubLookPath(t)
+	if err := playSound(soundIncorrect); err != nil {
+		t.Errorf("playSound() without player error = %v, want nil", err)
+	}
+	if len(*calls) != 1 {
+		t.Errorf("playSound() without player ran commands: %v", *calls)
+	}
+}
+
+// TestInitI18nMissingLanguage tests the fallback for languages without translations
+func TestInitI18nMissingLanguage(t *testing.T) {
+	var logged bytes.Buffer
+	log.SetOutput(&logged)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	localizer, err := initI18n("it")
+	if err != nil {
+		t.Fatalf("initI18n(it) error = %v", err)
+	}
+	if !strings.Contains(logged.String(), "No translations for 'it', falling back to English") {
+		t.Errorf("should warn about the missing translations, logged %q", logged.String())
+	}
+	if got := tr(localizer, "Correct"); got != "✅ Correct! Well done!" {
+		t.Errorf("tr(Correct) = %q, want the English message", got)
+	}
+
+	// Languages with a file, also regional variants, load without a warning
+	logged.Reset()
+	localizer, _ = initI18n("de-AT")
+	if logged.Len() != 0 || tr(localizer, "Correct") != "✅ Richtig! Gut gemacht!" {
+		t.Errorf("de-AT should use the German file, logged %q", logged.String())
+	}
+}
+
+// TestFormatWordDiffGermanLabels tests that longer German labels are neither
+// truncated nor misaligned
+func TestFormatWordDiffGermanLabels(t *testing.T) {
+	localizer, err := initI18n("de")
+	if err != nil {
+		t.Fatalf("initI18n(de) error = %v", err)
+	}
+
+	result := formatWordDiff("Hxus", "Haus", localizer)
+	lines := strings.Split(result, "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %d:\n%s", len(lines), result)
+	}
+
+	for i, label := range []string{"Deine Eingabe:", "Richtig:", "Unterschiede:"} {
+		if !strings.Contains(lines[i], label) {
+			t.Errorf("line %d = %q, want full label %q", i+1, lines[i], label)
+		}
+		// Equal widths mean the words start in the same column
+		if lipgloss.Width(lines[i]) != lipgloss.Width(lines[0]) {
+			t.Errorf("line %d is %d cells wide, want %d", i+1, lipgloss.Width(lines[i]), lipgloss.Width(lines[0]))
+		}
+	}
+}
+
+// TestMaxLabelWidth tests that label widths are measured in terminal cells
+func TestMaxLabelWidth(t *testing.T) {
+	if got := maxLabelWidth("Richtig:", "Unterschiede:"); got != 13 {
+		t.Errorf("maxLabelWidth() = %d, want 13", got)
+	}
+	// Wide runes take two cells each
+	if got := maxLabelWidth("正解:"); got != 5 {
+		t.Errorf("maxLabelWidth(wide) = %d, want 5", got)
+	}
+}
+
+// TestWriteJSONResult tests that JSON mode prints a parseable result without ANSI codes
+func TestWriteJSONResult(t *testing.T) {
+	config := defaultConfig()
+	config.Language = "en"
+	config.Words = testEntries("Haus", "Buch")
+
+	answersPath := writeTestConfig(t, "answers.txt", "Hau\nBuch\nHaus\n")
+	answers, err := loadAnswers(answersPath)
+	if err != nil {
+		t.Fatalf("loadAnswers() error = %v", err)
+	}
+
+	var out strings.Builder
+	if err := writeJSONResult(&config, setupTestLocalizer(), noneEngine{}, answers, &out); err != nil {
+		t.Fatalf("writeJSONResult() error = %v", err)
+	}
+	if strings.Contains(out.String(), "\x1b[") {
+		t.Errorf("JSON output contains ANSI escape codes:\n%s", out.String())
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal([]byte(out.String()), &result); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, out.String())
+	}
+	for _, field := range []string{"words", "word_count", "total_attempts", "correct_count", "accuracy", "best_streak", "completed"} {
+		if _, ok := result[field]; !ok {
+			t.Errorf("JSON output is missing %q", field)
+		}
+	}
+	if result["total_attempts"] != float64(3) || result["completed"] != true {
+		t.Errorf("total_attempts = %v, completed = %v, want 3 and true", result["total_attempts"], result["completed"])
+	}
+}
+
+// TestMissingTTSCommand tests the friendly error when the TTS binary is missing
+// An empty directory as PATH simulates a stripped environment without 'say'
+func TestMissingTTSCommand(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("PATH", dir)
+
+	_, err := ttsEngine("say")
+	if err == nil {
+		t.Fatal("ttsEngine(say) should fail when say is not on the PATH")
+	}
+	if !strings.Contains(err.Error(), "--tts-engine none") {
+		t.Errorf("error = %q, want a hint about --tts-engine none", err)
+	}
+	if _, silent := detectTTSEngine().(noneEngine); !silent {
+		t.Error("detectTTSEngine() should fall back to the silent engine")
+	}
+
+	// A (fake) say executable on the PATH is accepted
+	if err := os.WriteFile(filepath.Join(dir, "say"), []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatalf("Failed to write fake say: %v", err)
+	}
+	if _, err := ttsEngine("say"); err != nil {
+		t.Errorf("ttsEngine(say) with say on the PATH error = %v", err)
+	}
+}
+
+// TestLoadConfigPronunciation tests parsing the pronunciation of an entry
+func TestLoadConfigPronunciation(t *testing.T) {
+	path := writeTestConfig(t, "config.yaml", "words:\n  - word: live\n    pronunciation: liv\n")
+	config, err := loadConfig(path)
+	if err != nil {
+		t.Fatalf("loadConfig() error = %v", err)
+	}
+	entry := config.Words[0]
+	if entry.spokenText() != "liv" || entry.target(true) != "live" {
+		t.Errorf("spokenText() = %q, target() = %q, want liv and live", entry.spokenText(), entry.target(true))
+	}
+}
+
+// TestLoadConfigAutoAdvance tests parsing the auto-advance delay as a duration
+func TestLoadConfigAutoAdvance(t *testing.T) {
+	path := writeTestConfig(t, "config.yaml", "auto_advance: 1.5s\nwords:\n  - Haus\n")
+	config, err := loadConfig(path)
+	if err != nil {
+		t.Fatalf("loadConfig() error = %v", err)
+	}
+	if config.AutoAdvance != 1500*time.Millisecond {
+		t.Errorf("AutoAdvance = %v, want 1.5s", config.AutoAdvance)
+	}
+}
+
+// TestLoadConfigMode tests the mode default and validation
+func TestLoadConfigMode(t *testing.T) {
+	config, err := loadConfig(writeTestConfig(t, "config.yaml", "words:\n  - Haus\n"))
+	if err != nil {
+		t.Fatalf("loadConfig() error = %v", err)
+	}
+	if config.Mode != modeDictation {
+		t.Errorf("Mode = %q, want %q", config.Mode, modeDictation)
+	}
+
+	if _, err := loadConfig(writeTestConfig(t, "config.yaml", "mode: spelling\nwords:\n  - Haus\n")); err == nil {
+		t.Error("loadConfig() should reject an unknown mode")
+	}
+}
+
+// TestOrderWordsNoShuffle tests that without shuffling the config order is kept
+func TestOrderWordsNoShuffle(t *testing.T) {
+	words := []string{"Haus", "Buch", "Schule", "Freund", "Wasser"}
+
+	if got := strings.Join(orderWords(words, 0, false), ","); got != strings.Join(words, ",") {
+		t.Errorf("orderWords() = %s, want config order", got)
+	}
+	if got := strings.Join(orderWords(words, 2, false), ","); got != "Haus,Buch" {
+		t.Errorf("orderWords(2) = %s, want Haus,Buch", got)
+	}
+	if got := orderWords(words, 3, true); len(got) != 3 {
+		t.Errorf("orderWords(3, shuffle) returned %d words, want 3", len(got))
+	}
+}
+
+// TestEditDistance tests the Levenshtein distance on runes
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"Haus", "Haus", 0},
+		{"Hau", "Haus", 1},
+		{"Hasu", "Haus", 2},
+		{"Madchen", "Mädchen", 1},
+		{"", "Buch", 4},
+		{"Katze", "Haus", 4},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+// TestFormatWordDiffSeverity tests that near misses use the near-miss style
+// and far-off answers the wrong style
+func TestFormatWordDiffSeverity(t *testing.T) {
+	localizer := setupTestLocalizer()
+
+	// Colors are dropped without a terminal, so mark the styles with transforms
+	styles := defaultStyleSet()
+	styles.wrongChar = lipgloss.NewStyle().Transform(func(s string) string { return "!" + s })
+	styles.nearMissChar = lipgloss.NewStyle().Transform(func(s string) string { return "~" + s })
+
+	near := formatWordDiff("Hauz", "Haus", localizer, withStyles(styles), withSeverity(severityFor("Hauz", "Haus")))
+	if !strings.Contains(near, "~z") || strings.Contains(near, "!") {
+		t.Errorf("1-distance diff should use the near-miss style:\n%s", near)
+	}
+
+	far := formatWordDiff("Katze", "Haus", localizer, withStyles(styles), withSeverity(severityFor("Katze", "Haus")))
+	if !strings.Contains(far, "!K") || strings.Contains(far, "~") {
+		t.Errorf("far diff should use the wrong style:\n%s", far)
+	}
+}
+
+// TestPreviewWords tests that the preview speaks all words in list order
+func TestPreviewWords(t *testing.T) {
+	original := previewPause
+	previewPause = 0
+	t.Cleanup(func() { previewPause = original })
+
+	speaker := &recordingSpeaker{}
+	previewWords([]string{"Haus", "Buch", "Schule"}, "de", speaker)
+
+	if got := strings.Join(speaker.texts, ","); got != "Haus,Buch,Schule" {
+		t.Errorf("spoken = %s, want Haus,Buch,Schule", got)
+	}
+	if got := strings.Join(speaker.langs, ","); got != "de,de,de" {
+		t.Errorf("languages = %s, want de for every word", got)
+	}
+}
+
+// TestScoreFor tests the total score of a scripted sequence of outcomes
+func TestScoreFor(t *testing.T) {
+	outcomes := []Outcome{outcomeFirstTry, outcomeWrong, outcomeEventually, outcomeWrong, outcomeWrong, outcomeWithHint}
+
+	total := 0
+	for _, outcome := range outcomes {
+		total += scoreFor(outcome, defaultScoring())
+	}
+	if total != 17 {
+		t.Errorf("default total = %d, want 17", total)
+	}
+
+	custom := Scoring{FirstTry: 3, Eventually: 2, WithHint: 1, Wrong: -1}
+	total = 0
+	for _, outcome := range outcomes {
+		total += scoreFor(outcome, custom)
+	}
+	if total != 3 {
+		t.Errorf("custom total = %d, want 3", total)
+	}
+}
+
+// TestCorrectOutcome tests classifying correct answers
+func TestCorrectOutcome(t *testing.T) {
+	if correctOutcome(1, false) != outcomeFirstTry {
+		t.Error("1 attempt should be first try")
+	}
+	if correctOutcome(3, false) != outcomeEventually {
+		t.Error("3 attempts without hint should be eventually")
+	}
+	if correctOutcome(3, true) != outcomeWithHint {
+		t.Error("3 attempts after a hint should be with hint")
+	}
+}
+
+// TestRunSessionSinglePass tests that RunSession presents each word once
+func TestRunSessionSinglePass(t *testing.T) {
+	config := defaultConfig()
+	config.Language = "en"
+	config.SinglePass = true
+	config.Words = testEntries("Haus", "Buch")
+
+	result, err := RunSession(&config, setupTestLocalizer(), noneEngine{}, &scriptedAnswers{answers: []string{"Hau", "Buch", "Haus"}}, io.Discard)
+	if err != nil {
+		t.Fatalf("RunSession() error = %v", err)
+	}
+	if result.TotalAttempts != 2 || result.Accuracy != 50 || !result.Completed {
+		t.Errorf("result = %+v, want 2 attempts, 50%% accuracy, completed", result)
+	}
+}
+
+// TestWeightedSample tests that entries are drawn in proportion to their weight
+func TestWeightedSample(t *testing.T) {
+	seedRandom(42)
+	t.Cleanup(func() { seedRandom(time.Now().UnixNano()) })
+
+	words := []WordEntry{{Word: "und", Weight: 3}, {Word: "Eichhörnchen"}}
+	const draws = 4000
+	common := 0
+	for i := 0; i < draws; i++ {
+		sample := weightedSample(words, 1)
+		if len(sample) != 1 {
+			t.Fatalf("weightedSample(1) returned %d entries", len(sample))
+		}
+		if sample[0].Word == "und" {
+			common++
+		}
+	}
+
+	// Expect 3/4 of the draws; allow a few percent of random variation
+	share := float64(common) / draws
+	if share < 0.72 || share > 0.78 {
+		t.Errorf("weight 3 word drawn in %.2f of the draws, want about 0.75", share)
+	}
+
+	// Drawing all entries returns each exactly once
+	all := weightedSample(testEntries("Haus", "Buch", "Schule"), 0)
+	seen := make(map[string]bool)
+	for _, entry := range all {
+		seen[entry.Word] = true
+	}
+	if len(all) != 3 || len(seen) != 3 {
+		t.Errorf("weightedSample(0) = %v, want all 3 words once", all)
+	}
+}
+
+// TestFormatWordDiffWrapsLongPhrases tests that long phrases wrap in aligned blocks
+func TestFormatWordDiffWrapsLongPhrases(t *testing.T) {
+	localizer := setupTestLocalizer()
+	phrase := "Der Hund läuft schnell über die große grüne Wiese"
+
+	result := formatWordDiff(phrase+"x", phrase, localizer, withMaxWidth(40))
+	lines := strings.Split(result, "\n")
+	if len(lines) < 7 {
+		t.Fatalf("expected at least two blocks, got:\n%s", result)
+	}
+	for _, line := range lines {
+		if w := lipgloss.Width(line); w > 40 {
+			t.Errorf("line is %d cells wide, want at most 40: %q", w, line)
+		}
+	}
+
+	// The continuation block starts in the same column as the first block
+	labelWidth := maxLabelWidth("Your input:", "Correct:", "Differences:")
+	var correct strings.Builder
+	for i := 1; i < len(lines); i += 4 {
+		correct.WriteString(lines[i][labelWidth+2:])
+	}
+	if got := strings.TrimRight(correct.String(), " "); got != phrase {
+		t.Errorf("wrapped correct line = %q, want %q", got, phrase)
+	}
+}
+
+// TestPlainSession tests a plain-mode session driven by lines of input
+func TestPlainSession(t *testing.T) {
+	config := defaultConfig()
+	config.Language = "en"
+	config.Plain = true
+	config.Words = testEntries("Haus")
+
+	var out strings.Builder
+	result, err := RunSession(&config, setupTestLocalizer(), noneEngine{}, newLineAnswers(strings.NewReader("Hau\nHaus\n")), &out)
+	if err != nil {
+		t.Fatalf("RunSession() error = %v", err)
+	}
+	if !result.Completed || result.TotalAttempts != 2 {
+		t.Errorf("result = %+v, want completed after 2 attempts", result)
+	}
+
+	output := out.String()
+	if !strings.Contains(output, "The correct spelling is Haus, spelled H, a, u, s.") {
+		t.Errorf("output should spell out the correct word, got:\n%s", output)
+	}
+	if strings.Contains(output, "^") || strings.Contains(output, "\x1b[") {
+		t.Errorf("plain output should contain no diff or escape codes, got:\n%s", output)
+	}
+
+	printSummary(result, setupTestLocalizer(), &out)
+	if !strings.Contains(out.String(), "Accuracy: 50%") {
+		t.Errorf("summary should show the accuracy, got:\n%s", out.String())
+	}
+}
+
+// TestSpellOut tests spelling words and phrases letter by letter
+func TestSpellOut(t *testing.T) {
+	if got := spellOut("Bär"); got != "B, ä, r" {
+		t.Errorf("spellOut(Bär) = %q", got)
+	}
+	if got := spellOut("das Haus"); got != "d, a, s; H, a, u, s" {
+		t.Errorf("spellOut(das Haus) = %q", got)
+	}
+}
+
+// TestSpeakPhraseChunked tests that chunked phrases are spoken token by token
+func TestSpeakPhraseChunked(t *testing.T) {
+	original := phraseChunkPause
+	phraseChunkPause = 0
+	t.Cleanup(func() { phraseChunkPause = original })
+
+	speaker := &recordingSpeaker{}
+	if err := speakPhrase("der  kleine Hund", "de", defaultRate, true, speaker); err != nil {
+		t.Fatalf("speakPhrase() error = %v", err)
+	}
+	if got := strings.Join(speaker.texts, "|"); got != "der|kleine|Hund" {
+		t.Errorf("chunked speech = %s, want one call per token", got)
+	}
+
+	speaker = &recordingSpeaker{}
+	_ = speakPhrase("der kleine Hund", "de", defaultRate, false, speaker)
+	if len(speaker.texts) != 1 || speaker.texts[0] != "der kleine Hund" {
+		t.Errorf("unchunked speech = %v, want the whole phrase once", speaker.texts)
+	}
+}
+
+// TestApplyFocus tests that focus words lead the order and unknown ones are ignored
+func TestApplyFocus(t *testing.T) {
+	words := []string{"Schule", "Haus", "Freund", "Buch"}
+
+	got := applyFocus(words, []string{"Buch", "Zebra", "Haus", "Buch"})
+	if want := "Buch,Haus,Schule,Freund"; strings.Join(got, ",") != want {
+		t.Errorf("applyFocus() = %v, want %s", got, want)
+	}
+	if got := applyFocus(words, nil); strings.Join(got, ",") != strings.Join(words, ",") {
+		t.Errorf("applyFocus(nil) = %v, want the order unchanged", got)
+	}
+}
+
+// TestLoadConfigFromURL tests downloading a word list from a web server
+func TestLoadConfigFromURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/week3.yaml":
+			fmt.Fprint(w, "title: Week 3\nlanguage: de\nwords:\n  - packen\n  - Blitz\n")
+		case "/list.json":
+			fmt.Fprint(w, `{"language": "en", "words": ["house", {"word": "read", "pronunciation": "red"}]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	config, err := loadConfigFromURL(server.URL + "/week3.yaml")
+	if err != nil {
+		t.Fatalf("loadConfigFromURL() error = %v", err)
+	}
+	if config.Title != "Week 3" || strings.Join(config.wordList(), ",") != "packen,Blitz" {
+		t.Errorf("config = %+v, want Week 3 with packen and Blitz", config)
+	}
+
+	config, err = loadConfigFromURL(server.URL + "/list.json")
+	if err != nil {
+		t.Fatalf("loadConfigFromURL(json) error = %v", err)
+	}
+	if len(config.Words) != 2 || config.Words[1].Pronunciation != "red" {
+		t.Errorf("JSON config words = %+v", config.Words)
+	}
+
+	_, err = loadConfigFromURL(server.URL + "/missing.yaml")
+	if err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("loadConfigFromURL(missing) error = %v, want a 404 message", err)
+	}
+}
+
+// TestLoadConfigsMergesDefinitions tests that definitions from all files are kept
+func TestLoadConfigsMergesDefinitions(t *testing.T) {
+	first := writeTestConfig(t, "a.yaml", "words: [Haus]\ndefinitions:\n  Haus: Gebäude\n")
+	second := writeTestConfig(t, "b.yaml", "words: [Buch]\ndefinitions:\n  Buch: Seiten zum Lesen\n  Haus: anders\n")
+	config, err := loadConfigs([]string{first, second})
+	if err != nil {
+		t.Fatalf("loadConfigs() error = %v", err)
+	}
+	if config.Definitions["Haus"] != "Gebäude" || config.Definitions["Buch"] != "Seiten zum Lesen" {
+		t.Errorf("Definitions = %v", config.Definitions)
+	}
+}
+
+// TestIgnoreTrailingPunctuation tests phrases with and without final punctuation
+func TestIgnoreTrailingPunctuation(t *testing.T) {
+	tests := []struct {
+		input, target string
+		ignore        bool
+		want          bool
+	}{
+		{"Der Hund bellt", "Der Hund bellt.", false, false},
+		{"Der Hund bellt", "Der Hund bellt.", true, true},
+		{"Wie spät ist es?", "Wie spät ist es", false, false},
+		{"Wie spät ist es?", "Wie spät ist es", true, true},
+		{"Halt!?", "Halt!", true, true},
+		{"Der Hund bellt.", "Der Hund bellt.", false, true},
+		{"Der Hund bellt,", "Der Hund bellt.", true, false}, // Commas still count
+	}
+	for _, tt := range tests {
+		config := defaultConfig()
+		config.IgnoreTrailingPunctuation = tt.ignore
+		if got := answerMatches(tt.input, tt.target, config.Language, &config); got != tt.want {
+			t.Errorf("answerMatches(%q, %q, ignore=%v) = %v, want %v", tt.input, tt.target, tt.ignore, got, tt.want)
+		}
+	}
+
+	if got := trimTrailingPunct("Komm her!"); got != "Komm her" {
+		t.Errorf("trimTrailingPunct() = %q, want %q", got, "Komm her")
+	}
+}
+
+// TestCollapseWhitespace tests answers with extra spaces in phrases
+func TestCollapseWhitespace(t *testing.T) {
+	tests := []struct {
+		input, target string
+		collapse      bool
+		want          bool
+	}{
+		{"the  fox", "the fox", false, false},
+		{"the  fox", "the fox", true, true},
+		{"  the fox", "the fox", true, true},   // Leading
+		{"the fox \t", "the fox", true, true},  // Trailing
+		{"the \t fox", "the fox", true, true},  // Tabs inside
+		{"thefox", "the fox", true, false},     // Spaces can't be left out
+		{"the fox", "the fox", false, true},
+	}
+	for _, tt := range tests {
+		config := defaultConfig()
+		config.CollapseWhitespace = tt.collapse
+		if got := answerMatches(tt.input, tt.target, config.Language, &config); got != tt.want {
+			t.Errorf("answerMatches(%q, %q, collapse=%v) = %v, want %v", tt.input, tt.target, tt.collapse, got, tt.want)
+		}
+	}
+
+	if got := normalizeWhitespace("  Der   Hund\tbellt "); got != "Der Hund bellt" {
+		t.Errorf("normalizeWhitespace() = %q, want %q", got, "Der Hund bellt")
+	}
+}
+
+// TestExampleConfigs tests the bundled example configs
+func TestExampleConfigs(t *testing.T) {
+	names := exampleNames()
+	want := []string{"english-spelling", "german-basics"}
+	if fmt.Sprint(names) != fmt.Sprint(want) {
+		t.Fatalf("exampleNames() = %v, want %v", names, want)
+	}
+
+	// Every example must be a valid config
+	dir := t.TempDir()
+	for _, name := range names {
+		var buf bytes.Buffer
+		if err := writeExampleConfig(name, &buf); err != nil {
+			t.Fatalf("writeExampleConfig(%q) error: %v", name, err)
+		}
+		path := filepath.Join(dir, name+".yaml")
+		if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := loadConfig(path); err != nil {
+			t.Errorf("example %q does not load: %v", name, err)
+		}
+	}
+
+	err := writeExampleConfig("klingon", io.Discard)
+	if err == nil || !strings.Contains(err.Error(), "german-basics") {
+		t.Errorf("unknown example should list the available ones, got %v", err)
+	}
+}
+
+// TestSzEquivalence tests accepting "ss" for "ß" in German lists
+func TestSzEquivalence(t *testing.T) {
+	tests := []struct {
+		input, target string
+		language      string
+		enabled       bool
+		want          bool
+	}{
+		{"Strasse", "Straße", "de", false, false},
+		{"Strasse", "Straße", "de", true, true},
+		{"Fuß", "Fuss", "de", true, true}, // Other direction
+		{"STRASSE", "STRAẞE", "de", true, true},
+		{"Strase", "Straße", "de", true, false}, // One s is still wrong
+		{"Strasse", "Straße", "en", true, false}, // Only for German
+	}
+	for _, tt := range tests {
+		config := defaultConfig()
+		config.Language = tt.language
+		config.SzEquivalence = tt.enabled
+		if got := answerMatches(tt.input, tt.target, config.Language, &config); got != tt.want {
+			t.Errorf("answerMatches(%q, %q, %s, enabled=%v) = %v, want %v", tt.input, tt.target, tt.language, tt.enabled, got, tt.want)
+		}
+	}
+}
+
+// TestTrimSurroundingQuotes tests removing straight and smart quote pairs
+func TestTrimSurroundingQuotes(t *testing.T) {
+	tests := []struct {
+		input, want string
+	}{
+		{`"Haus"`, "Haus"},
+		{"'Haus'", "Haus"},
+		{"“Haus”", "Haus"},
+		{"‘Haus’", "Haus"},
+		{"„Haus“", "Haus"},
+		{"«Haus»", "Haus"},
+		{`""Haus""`, `"Haus"`},  // Only one pair
+		{`"Haus`, `"Haus`},      // No partner
+		{`"Haus'`, `"Haus'`},    // Different quotes
+		{"Peter's", "Peter's"},
+		{`"`, `"`},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := trimSurroundingQuotes(tt.input); got != tt.want {
+			t.Errorf("trimSurroundingQuotes(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+
+	config := defaultConfig()
+	config.TrimQuotes = true
+	if !answerMatches("„Haus“", "Haus", config.Language, &config) {
+		t.Error("quoted answers should match with TrimQuotes")
+	}
+	config.TrimQuotes = false
+	if answerMatches("„Haus“", "Haus", config.Language, &config) {
+		t.Error("quoted answers should not match without TrimQuotes")
+	}
+}
+
+// TestFoldLigatures tests ligatures and apostrophe variants
+func TestFoldLigatures(t *testing.T) {
+	tests := []struct {
+		input, target string
+	}{
+		{"coeur", "cœur"},
+		{"cœur", "coeur"},
+		{"Caesar", "Cæsar"},
+		{"l'école", "l’école"},
+		{"l’école", "l'école"},
+		{"don't", "donʼt"},
+	}
+	config := defaultConfig()
+	config.Language = "fr"
+	for _, tt := range tests {
+		config.FoldLigatures = false
+		if answerMatches(tt.input, tt.target, config.Language, &config) {
+			t.Errorf("%q should not match %q without FoldLigatures", tt.input, tt.target)
+		}
+		config.FoldLigatures = true
+		if !answerMatches(tt.input, tt.target, config.Language, &config) {
+			t.Errorf("%q should match %q with FoldLigatures", tt.input, tt.target)
+		}
+	}
+	if got := foldLigatures("Œuvre"); got != "OEuvre" {
+		t.Errorf("foldLigatures(Œuvre) = %q, want OEuvre", got)
+	}
+}
+
+// TestBuildNormalizer tests that the comparison relaxations combine
+func TestBuildNormalizer(t *testing.T) {
+	tests := []struct {
+		input, target      string
+		ignoreCase         bool
+		diacriticsOptional bool
+		want               bool
+	}{
+		{"madchen", "Mädchen", false, false, false},
+		{"madchen", "Mädchen", true, false, false},  // Case alone isn't enough
+		{"madchen", "Mädchen", false, true, false},  // Neither are diacritics
+		{"madchen", "Mädchen", true, true, true},
+		{"MÄDCHEN", "Mädchen", true, false, true},
+		{"Madchen", "Mädchen", false, true, true},
+		{"madchem", "Mädchen", true, true, false},   // A wrong letter stays wrong
+	}
+	for _, tt := range tests {
+		config := defaultConfig()
+		config.Language = "de"
+		config.IgnoreCase = tt.ignoreCase
+		config.DiacriticsOptional = tt.diacriticsOptional
+		if got := answerMatches(tt.input, tt.target, config.Language, &config); got != tt.want {
+			t.Errorf("answerMatches(%q, %q, case=%v, diacritics=%v) = %v, want %v", tt.input, tt.target, tt.ignoreCase, tt.diacriticsOptional, got, tt.want)
+		}
+	}
+
+	// Steps run in order: spaces and punctuation first, then the letters
+	config := defaultConfig()
+	config.Language = "de"
+	config.CollapseWhitespace = true
+	config.IgnoreTrailingPunctuation = true
+	config.IgnoreCase = true
+	config.SzEquivalence = true
+	if got := buildNormalizer(&config, config.Language)("  Die  STRAẞE. "); got != "die strasse" {
+		t.Errorf("normalized = %q, want %q", got, "die strasse")
+	}
+
+	// Sibling words are found with the same relaxations
+	config.Words = testEntries("to", "too")
+	if !config.isSiblingWord("TOO", "to") {
+		t.Error("TOO should be recognized as the sibling of to with ignore_case")
+	}
+}
+
+// TestNumbers tests numerals and number words as equivalent answers
+func TestNumbers(t *testing.T) {
+	tests := []struct {
+		input, target string
+		language      string
+		enabled       bool
+		want          bool
+	}{
+		{"three", "3", "en", false, false},
+		{"three", "3", "en", true, true},
+		{"3", "three", "en", true, true}, // Other direction
+		{"Three apples", "3 apples", "en", true, true},
+		{"drei", "3", "de", true, true},
+		{"drei", "three", "de", true, false}, // English words only in English lists
+		{"drei", "3", "en", true, false},
+		{"four", "3", "en", true, false},
+	}
+	for _, tt := range tests {
+		config := defaultConfig()
+		config.Language = tt.language
+		config.Numbers = tt.enabled
+		if got := answerMatches(tt.input, tt.target, config.Language, &config); got != tt.want {
+			t.Errorf("answerMatches(%q, %q, %s, enabled=%v) = %v, want %v", tt.input, tt.target, tt.language, tt.enabled, got, tt.want)
+		}
+	}
+
+	// Numerals are spoken spelled out
+	path := writeTestConfig(t, "numbers.yaml", "language: de\nnumbers: true\nwords: [\"3 Äpfel\", \"25\", {word: \"7\", pronunciation: sieben!}]\n")
+	config, err := loadConfigs([]string{path})
+	if err != nil {
+		t.Fatalf("loadConfigs error: %v", err)
+	}
+	for i, want := range []string{"drei Äpfel", "25", "sieben!"} {
+		if got := config.Words[i].spokenText(); got != want {
+			t.Errorf("spokenText(%q) = %q, want %q", config.Words[i].Word, got, want)
+		}
+	}
+}
+
+// TestTypingHintsFor tests the keyboard hints for special characters
+func TestTypingHintsFor(t *testing.T) {
+	hints := typingHintsFor("Tür", "darwin")
+	if want := []string{"ü: ⌥+u u"}; !reflect.DeepEqual(hints, want) {
+		t.Errorf("typingHintsFor(Tür, darwin) = %q, want %q", hints, want)
+	}
+
+	// Every character once, in the order of the word
+	hints = typingHintsFor("Grüße über", "windows")
+	if want := []string{"ü: Alt+0252", "ß: Alt+0223"}; !reflect.DeepEqual(hints, want) {
+		t.Errorf("typingHintsFor(Grüße über, windows) = %q, want %q", hints, want)
+	}
+
+	if hints := typingHintsFor("Haus", "darwin"); len(hints) != 0 {
+		t.Errorf("plain words need no hints, got %q", hints)
+	}
+	if hints := typingHintsFor("Tür", "plan9"); len(hints) != 0 {
+		t.Errorf("unknown systems have no hints, got %q", hints)
+	}
+}
+
+// TestLoadConfigsMergesHints tests that later files complete the rhymes and phonetics
+func TestLoadConfigsMergesHints(t *testing.T) {
+	first := writeTestConfig(t, "first.yaml", "words: [Haus]\nrhymes:\n  Haus: Maus\nphonetics:\n  Haus: haʊs\n")
+	second := writeTestConfig(t, "second.yaml", "words: [Nacht]\nrhymes:\n  Haus: Laus\n  Nacht: acht\nphonetics:\n  Nacht: naxt\n")
+	config, err := loadConfigs([]string{first, second})
+	if err != nil {
+		t.Fatalf("loadConfigs error: %v", err)
+	}
+	if want := map[string]string{"Haus": "Maus", "Nacht": "acht"}; !reflect.DeepEqual(config.Rhymes, want) {
+		t.Errorf("rhymes = %v, want %v", config.Rhymes, want)
+	}
+	if want := map[string]string{"Haus": "haʊs", "Nacht": "naxt"}; !reflect.DeepEqual(config.Phonetics, want) {
+		t.Errorf("phonetics = %v, want %v", config.Phonetics, want)
+	}
+}
+
+// TestVoiceSelection tests configured voices and their random choice
+func TestVoiceSelection(t *testing.T) {
+	var config Config
+	data := "words: [Haus]\nvoices:\n  de: [Anna, Petra, Markus]\n  en: Samantha\n"
+	if err := yaml.Unmarshal([]byte(data), &config); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if got := config.Voices["en"]; len(got) != 1 || got[0] != "Samantha" {
+		t.Errorf("single voice = %v, want [Samantha]", got)
+	}
+
+	// The same seed picks the same voices
+	pick := func() []string {
+		var voices []string
+		for i := 0; i < 10; i++ {
+			voices = append(voices, getVoiceForLanguage("de", config.Voices))
+		}
+		return voices
+	}
+	seedRandom(42)
+	first := pick()
+	seedRandom(42)
+	second := pick()
+	t.Cleanup(func() { seedRandom(time.Now().UnixNano()) })
+	if fmt.Sprint(first) != fmt.Sprint(second) {
+		t.Errorf("seeded voices differ: %v and %v", first, second)
+	}
+	for _, voice := range first {
+		if voice != "Anna" && voice != "Petra" && voice != "Markus" {
+			t.Errorf("picked unknown voice %q", voice)
+		}
+	}
+
+	if got := getVoiceForLanguage("en", config.Voices); got != "Samantha" {
+		t.Errorf("configured voice = %q, want Samantha", got)
+	}
+	// Languages without configured voices keep the built-in voice
+	if got := getVoiceForLanguage("he", config.Voices); got != "Carmit" {
+		t.Errorf("built-in voice = %q, want Carmit", got)
+	}
+}
+
+// TestVoicesWhileShuffling tests that picking voices in background
+// commands doesn't share a generator with the shuffling (go test -race)
+func TestVoicesWhileShuffling(t *testing.T) {
+	voices := map[string]voiceList{"de": {"Anna", "Petra"}}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			getVoiceForLanguage("de", voices)
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		shuffleWords([]string{"Haus", "Buch", "Schule"})
+	}
+	<-done
+}
+
+// TestRunSessionMaxAttempts tests giving up on a word in the plain session
+func TestRunSessionMaxAttempts(t *testing.T) {
+	oldPause := spellLetterPause
+	spellLetterPause = 0
+	t.Cleanup(func() { spellLetterPause = oldPause })
+
+	config := defaultConfig()
+	config.Language = "en"
+	config.Words = testEntries("Bär")
+	config.MaxAttempts = 2
+	speaker := &recordingSpeaker{}
+	answers := &scriptedAnswers{answers: []string{"Bar", "Baer", "Bär"}}
+	var out strings.Builder
+
+	result, err := RunSession(&config, setupTestLocalizer(), speaker, answers, &out)
+	if err != nil {
+		t.Fatalf("RunSession() error = %v", err)
+	}
+	if result.TotalAttempts != 2 || result.Words[0].Correct {
+		t.Errorf("result = %+v, want 2 wrong attempts and no further question", result)
+	}
+	// The word for both attempts, then its letters
+	if got := strings.Join(speaker.texts, ","); got != "Bär,Bär,B,ä,r" {
+		t.Errorf("spoken = %s, want Bär,Bär,B,ä,r", got)
+	}
+	if !strings.Contains(out.String(), "B, ä, r") {
+		t.Errorf("output should spell the word, got:\n%s", out.String())
+	}
+
+	// Correct answers of an earlier loop are no misses
+	config.Loops = 2
+	answers = &scriptedAnswers{answers: []string{"Bär", "Bar", "Bär"}}
+	result, err = RunSession(&config, setupTestLocalizer(), speaker, answers, io.Discard)
+	if err != nil {
+		t.Fatalf("RunSession() error = %v", err)
+	}
+	if result.TotalAttempts != 3 || !result.Words[0].Correct || result.Words[0].Misses != 1 {
+		t.Errorf("result = %+v, want the word asked again after one miss in the second loop", result)
+	}
+}
+
+// TestOptionalLeadingArticle tests accepting answers with or without an article
+func TestOptionalLeadingArticle(t *testing.T) {
+	tests := []struct {
+		input, target string
+		language      string
+		want          bool
+	}{
+		{"cat", "the cat", "en", true},
+		{"the cat", "cat", "en", true},
+		{"A cat", "the cat", "en", true},
+		{"an apple", "apple", "en", true},
+		{"Haus", "das Haus", "de", true},
+		{"die Katze", "Katze", "de", true},
+		{"Hause", "das Haus", "de", false},
+		{"das Haus", "Haus", "en", false}, // German articles only for German
+	}
+	for _, tt := range tests {
+		config := defaultConfig()
+		config.Language = tt.language
+		config.OptionalLeadingArticle = true
+		if got := answerMatches(tt.input, tt.target, config.Language, &config); got != tt.want {
+			t.Errorf("answerMatches(%q, %q, %s) = %v, want %v", tt.input, tt.target, tt.language, got, tt.want)
+		}
+	}
+
+	// Without the option the article has to match
+	config := defaultConfig()
+	config.Language = "en"
+	if answerMatches("cat", "the cat", config.Language, &config) {
+		t.Error("articles should only be optional when enabled")
+	}
+
+	if got := stripLeadingArticle("the", "en"); got != "the" {
+		t.Errorf("stripLeadingArticle(the) = %q, a lone article should be kept", got)
+	}
+	if got := stripLeadingArticle("Theater", "en"); got != "Theater" {
+		t.Errorf("stripLeadingArticle(Theater) = %q, want it unchanged", got)
+	}
+
+	// The diff compares the forms without the article
+	config.OptionalLeadingArticle = true
+	if input, target := diffTexts("cta", "the cat", config.Language, &config); input != "cta" || target != "cat" {
+		t.Errorf("diffTexts() = %q, %q, want cta, cat", input, target)
+	}
+}
+
+// TestSummarizeHistory tests the aggregates over a history file
+func TestSummarizeHistory(t *testing.T) {
+	fixture := `{"time":"2026-10-01T10:00:00Z","language":"de","words":[{"word":"Haus","attempts":3,"misses":1,"correct":true},{"word":"Vieh","attempts":2,"misses":2,"correct":false}],"word_count":2,"total_attempts":5,"correct_count":1,"accuracy":20}
+
+{"time":"2026-10-02T10:00:00Z","language":"de","words":[{"word":"Vieh","attempts":2,"misses":1,"correct":true},{"word":"Buch","attempts":1,"correct":true},{"word":"Haus","attempts":1,"correct":true},{"word":"Zug","attempts":2,"misses":1,"correct":true}],"word_count":4,"total_attempts":6,"correct_count":4,"accuracy":66}
+`
+	summary, err := summarizeHistory(strings.NewReader(fixture))
+	if err != nil {
+		t.Fatalf("summarizeHistory() error = %v", err)
+	}
+
+	if summary.Sessions != 2 {
+		t.Errorf("Sessions = %d, want 2", summary.Sessions)
+	}
+	if summary.Accuracy != 45 { // 5 of 11 attempts
+		t.Errorf("Accuracy = %d, want 45", summary.Accuracy)
+	}
+	if fmt.Sprint(summary.Trend) != "[20 66]" {
+		t.Errorf("Trend = %v, want [20 66]", summary.Trend)
+	}
+	if summary.AverageWords != 3 {
+		t.Errorf("AverageWords = %v, want 3", summary.AverageWords)
+	}
+	// Vieh: 2 misses + 1, Haus: 1 (spelled correctly twice in a loop),
+	// Zug: 1, Buch: none
+	want := "[{Vieh 3} {Haus 1} {Zug 1}]"
+	if got := fmt.Sprint(summary.MostMissed); got != want {
+		t.Errorf("MostMissed = %s, want %s", got, want)
+	}
+	if summary.Language != "de" {
+		t.Errorf("Language = %q, want de", summary.Language)
+	}
+
+	if _, err := summarizeHistory(strings.NewReader("{}\nnot json\n")); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("invalid line should be reported with its number, got %v", err)
+	}
+}
+
+// TestAppendHistory tests that sessions are appended and can be summarized
+func TestAppendHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	result := SessionResult{
+		Words:         []WordResult{{Word: "Haus", Attempts: 2, Misses: 1, Correct: true}},
+		WordCount:     1,
+		TotalAttempts: 2,
+		CorrectCount:  1,
+		Accuracy:      50,
+	}
+	for i := 0; i < 2; i++ {
+		if err := appendHistory(path, HistoryEntry{Time: time.Now(), Language: "en", SessionResult: result}); err != nil {
+			t.Fatalf("appendHistory() error = %v", err)
+		}
+	}
+
+	summary, err := loadHistorySummary(path)
+	if err != nil {
+		t.Fatalf("loadHistorySummary() error = %v", err)
+	}
+	if summary.Sessions != 2 || summary.MostMissed[0].Misses != 2 {
+		t.Errorf("summary = %+v, want 2 sessions and 2 misses of Haus", summary)
+	}
+
+	localizer, _ := initI18n("en")
+	var out strings.Builder
+	printHistorySummary(summary, localizer, &out)
+	for _, want := range []string{"Sessions", "50% → 50%", "Haus"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("stats output should contain %q, got:\n%s", want, out.String())
+		}
+	}
+}
+
+// TestDedupeWords tests removing exact and case-variant duplicates
+func TestDedupeWords(t *testing.T) {
+	unique, dups := dedupeWords([]string{"Haus", "Buch", "haus", "Buch", "HAUS", "Schule"})
+	if got := strings.Join(unique, ","); got != "Haus,Buch,Schule" {
+		t.Errorf("unique = %s, want Haus,Buch,Schule", got)
+	}
+	if got := strings.Join(dups, ","); got != "haus,Buch,HAUS" {
+		t.Errorf("dups = %s, want haus,Buch,HAUS", got)
+	}
+}
+
+// TestLoadConfigReportsDuplicates tests the duplicate warning of loadConfig
+func TestLoadConfigReportsDuplicates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := "words: [Haus, Buch, Haus, haus]\n"
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// By default duplicates are only reported
+	config, err := loadConfig(path)
+	if err != nil {
+		t.Fatalf("loadConfig() error = %v", err)
+	}
+	if got := strings.Join(config.wordList(), ","); got != "Haus,Buch,Haus,haus" {
+		t.Errorf("words = %s, want Haus,Buch,Haus,haus", got)
+	}
+	warning := config.Duplicates.String()
+	for _, want := range []string{"duplicate words: Haus", "only differ in case: haus", "dedupe_words"} {
+		if !strings.Contains(warning, want) {
+			t.Errorf("warning %q should contain %q", warning, want)
+		}
+	}
+
+	if err := os.WriteFile(path, []byte(data+"dedupe_words: true\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config, err = loadConfig(path)
+	if err != nil {
+		t.Fatalf("loadConfig() error = %v", err)
+	}
+	if got := strings.Join(config.wordList(), ","); got != "Haus,Buch" {
+		t.Errorf("deduped words = %s, want Haus,Buch", got)
+	}
+	if !strings.Contains(config.Duplicates.String(), "Haus (removed)") || !strings.Contains(config.Duplicates.String(), "haus (removed)") {
+		t.Errorf("warning should say the duplicates were removed, got %q", config.Duplicates)
+	}
+}
+
+// TestFilterWordsByPattern tests selecting words by a spelling pattern
+func TestFilterWordsByPattern(t *testing.T) {
+	words := []string{"Schule", "Tasche", "Haus", "Quelle", "bequem"}
+
+	got, err := filterWordsByPattern(words, "sch")
+	if err != nil {
+		t.Fatalf("filterWordsByPattern(sch) error = %v", err)
+	}
+	if strings.Join(got, ",") != "Tasche" {
+		t.Errorf("sch matched %v, want [Tasche] (case-sensitive)", got)
+	}
+
+	got, _ = filterWordsByPattern(words, "(?i)qu")
+	if strings.Join(got, ",") != "Quelle,bequem" {
+		t.Errorf("(?i)qu matched %v, want [Quelle bequem]", got)
+	}
+
+	if _, err := filterWordsByPattern(words, "sch("); err == nil || !strings.Contains(err.Error(), "invalid --pattern") {
+		t.Errorf("invalid regex should be an error, got %v", err)
+	}
+	if _, err := filterWordsByPattern(words, "xyz"); err == nil || !strings.Contains(err.Error(), "no words") {
+		t.Errorf("no matches should be an error, got %v", err)
+	}
+}
+
+// TestBucketedShuffle tests the easy-to-hard ramp
+func TestBucketedShuffle(t *testing.T) {
+	var entries []WordEntry
+	for i := 0; i < 6; i++ {
+		entries = append(entries,
+			WordEntry{Word: fmt.Sprintf("hard%d", i), Difficulty: "hard"},
+			WordEntry{Word: fmt.Sprintf("easy%d", i), Difficulty: "easy"},
+			WordEntry{Word: fmt.Sprintf("medium%d", i)}) // Unset counts as medium
+	}
+	t.Cleanup(func() { seedRandom(time.Now().UnixNano()) })
+
+	orders := make(map[string]bool)
+	for seed := int64(1); seed <= 5; seed++ {
+		seedRandom(seed)
+		ramped := bucketedShuffle(entries)
+		if len(ramped) != len(entries) {
+			t.Fatalf("ramp has %d words, want %d", len(ramped), len(entries))
+		}
+		for i, entry := range ramped {
+			var want string
+			switch {
+			case i < 6:
+				want = "easy"
+			case i < 12:
+				want = "medium"
+			default:
+				want = "hard"
+			}
+			if !strings.HasPrefix(entry.Word, want) {
+				t.Fatalf("seed %d: position %d is %s, want a %s word", seed, i, entry.Word, want)
+			}
+		}
+		orders[fmt.Sprint(ramped)] = true
+	}
+	if len(orders) < 2 {
+		t.Error("the order within a level should vary with the seed")
+	}
+
+	// The same seed gives the same ramp
+	seedRandom(7)
+	first := fmt.Sprint(bucketedShuffle(entries))
+	seedRandom(7)
+	if second := fmt.Sprint(bucketedShuffle(entries)); first != second {
+		t.Error("the same seed should give the same order")
+	}
+}
+
+// countingSynthesizer is a fake synthesizer that counts its calls
+type countingSynthesizer struct {
+	recordingSpeaker
+	synthesized []string
+}
+
+func (c *countingSynthesizer) Synthesize(text, langCode, path string, rate int) error {
+	c.synthesized = append(c.synthesized, text)
+	return os.WriteFile(path, []byte(text), 0644)
+}
+
+// TestCachingSpeaker tests that repeated words are synthesized only once
+func TestCachingSpeaker(t *testing.T) {
+	calls := stubRunCommand(t)
+	engine := &countingSynthesizer{}
+	cache, err := newCachingSpeaker(engine, "afplay")
+	if err != nil {
+		t.Fatalf("newCachingSpeaker() error = %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := cache.Speak("Haus", "de", defaultRate); err != nil {
+			t.Fatalf("Speak() error = %v", err)
+		}
+	}
+	if len(engine.synthesized) != 1 {
+		t.Errorf("Haus was synthesized %d times, want once", len(engine.synthesized))
+	}
+	if len(*calls) != 3 || (*calls)[0][0] != "afplay" {
+		t.Errorf("the recording should be played 3 times, got %v", *calls)
+	}
+
+	// Another rate is another recording
+	_ = cache.Speak("Haus", "de", slowRate)
+	if len(engine.synthesized) != 2 {
+		t.Errorf("slow Haus should get its own recording, synthesized %v", engine.synthesized)
+	}
+	if len(engine.texts) != 0 {
+		t.Errorf("the engine should not speak directly, spoke %v", engine.texts)
+	}
+
+	// The recordings are removed at the end of the session
+	if err := cache.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if _, err := os.Stat(cache.dir); !os.IsNotExist(err) {
+		t.Error("Close() should remove the cache directory")
+	}
+}
+
+// TestHasVoiceChoice tests detecting random voices, which can't be cached
+func TestHasVoiceChoice(t *testing.T) {
+	config := defaultConfig()
+	if config.hasVoiceChoice() {
+		t.Error("no configured voices should not be a choice")
+	}
+	config.Voices = map[string]voiceList{"de": {"Anna"}, "en": {"Alex"}}
+	if config.hasVoiceChoice() {
+		t.Error("one voice per language should not be a choice")
+	}
+	config.Voices["de"] = voiceList{"Anna", "Petra"}
+	if !config.hasVoiceChoice() {
+		t.Error("two German voices should be a choice")
+	}
+}
+
+// TestAcceptAlternatives tests words with several correct spellings
+func TestAcceptAlternatives(t *testing.T) {
+	config := defaultConfig()
+	entry := WordEntry{Word: "colour", Accept: []string{"color"}}
+	answers := entry.acceptedAnswers(false)
+
+	for _, input := range []string{"colour", "color"} {
+		if answer, ok := matchAnswer(input, answers, config.Language, &config); !ok || answer != input {
+			t.Errorf("matchAnswer(%q) = %q, %v, want it accepted", input, answer, ok)
+		}
+	}
+
+	// Wrong answers are compared with the closest spelling
+	tests := []struct{ input, closest string }{
+		{"colr", "color"},
+		{"coloure", "colour"},
+		{"colur", "colour"}, // Same distance: the primary spelling wins
+	}
+	for _, tt := range tests {
+		answer, ok := matchAnswer(tt.input, answers, config.Language, &config)
+		if ok || answer != tt.closest {
+			t.Errorf("matchAnswer(%q) = %q, %v, want %q and wrong", tt.input, answer, ok, tt.closest)
+		}
+	}
+
+	// With the article required, the alternatives need it too
+	entry = WordEntry{Word: "Portemonnaie", Article: "das", Accept: []string{"Portmonee"}}
+	if got := strings.Join(entry.acceptedAnswers(true), ","); got != "das Portemonnaie,das Portmonee" {
+		t.Errorf("acceptedAnswers(true) = %s", got)
+	}
+}
+
+// TestCollateWords tests language-aware sorting of German words
+func TestCollateWords(t *testing.T) {
+	words := []string{"Zug", "Äpfel", "Bär", "apfel", "Ofen", "Öl", "Baum"}
+	got := strings.Join(collateWords(words, "de"), ",")
+	// Umlauts sort next to their base letter, case is ignored
+	want := "apfel,Äpfel,Bär,Baum,Ofen,Öl,Zug"
+	if got != want {
+		t.Errorf("collateWords(de) = %s, want %s", got, want)
+	}
+	if words[0] != "Zug" {
+		t.Error("collateWords() should not change its argument")
+	}
+}
+
+// TestGroupByInitial tests bucketing words by their first letter
+func TestGroupByInitial(t *testing.T) {
+	groups := groupByInitial([]string{"Haus", "Übung", "hund", "Apfel", "überall", "", "Ärger"})
+	want := map[rune][]string{
+		'H': {"Haus", "hund"},
+		'Ü': {"Übung", "überall"},
+		'A': {"Apfel"},
+		'Ä': {"Ärger"},
+	}
+	if !reflect.DeepEqual(groups, want) {
+		t.Errorf("groupByInitial() = %v, want %v", groups, want)
+	}
+}
+
+// TestTranspositionMarker tests marking swapped neighbor letters in the diff
+func TestTranspositionMarker(t *testing.T) {
+	localizer, _ := initI18n("en")
+
+	tests := []struct {
+		input, correct string
+		swaps          string
+	}{
+		{"Huas", "Haus", "[1]"},
+		{"aHsu", "Haus", "[0 2]"},        // Two swaps
+		{"Shcule", "Schule", "[1]"},
+		{"Hasu", "Haus", "[2]"},
+		{"Haus", "Haus", "[]"},
+		{"Hous", "Haus", "[]"},           // A wrong letter is no swap
+		{"Haaus", "Haus", "[]"},          // Neither is a doubled one
+	}
+	for _, tt := range tests {
+		if got := fmt.Sprint(findTranspositions([]rune(tt.input), []rune(tt.correct))); got != tt.swaps {
+			t.Errorf("findTranspositions(%q, %q) = %s, want %s", tt.input, tt.correct, got, tt.swaps)
+		}
+	}
+
+	diff := formatWordDiff("aHsu", "Haus", localizer, withTranspositions(true))
+	if n := strings.Count(diff, swapMarker); n != 5 { // 4 letters + the note
+		t.Errorf("diff should mark both swaps, found %d markers:\n%s", n, diff)
+	}
+	if !strings.Contains(diff, "two letters are swapped") {
+		t.Errorf("diff should explain the marker:\n%s", diff)
+	}
+	if diff := formatWordDiff("Huas", "Haus", localizer); strings.Contains(diff, swapMarker) {
+		t.Error("swaps should only be marked when enabled")
+	}
+}
+
+// TestAddFavorite tests that favorites are saved once per word
+func TestAddFavorite(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	if favorites, err := loadFavorites(); err != nil || len(favorites) != 0 {
+		t.Fatalf("loadFavorites() without a file = %v, %v, want no favorites", favorites, err)
+	}
+	for _, word := range []string{"Rhythmus", "Vieh", "Rhythmus"} {
+		if err := addFavorite(word); err != nil {
+			t.Fatalf("addFavorite(%q) error: %v", word, err)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(home, ".dictation", "favorites.txt"))
+	if err != nil {
+		t.Fatalf("favorites file not written: %v", err)
+	}
+	if got, want := string(data), "Rhythmus\nVieh\n"; got != want {
+		t.Errorf("favorites file = %q, want %q", got, want)
+	}
+
+	// Only favorites that are in the list are practiced
+	config := &Config{Words: testEntries("Haus", "Vieh")}
+	favorites, _ := loadFavorites()
+	if words := favoriteWords(config, favorites); !reflect.DeepEqual(words, []string{"Vieh"}) {
+		t.Errorf("favoriteWords() = %v, want [Vieh]", words)
+	}
+}
+
+// TestGenerateWorksheet tests the numbered blanks and the answer key
+func TestGenerateWorksheet(t *testing.T) {
+	config := &Config{
+		Title:       "Week 3",
+		Language:    "en",
+		Words:       testEntries("Haus", "Buch", "Schule"),
+		Definitions: map[string]string{"Buch": "You read it"},
+	}
+	var out strings.Builder
+	if err := generateWorksheet(&out, config); err != nil {
+		t.Fatalf("generateWorksheet() error: %v", err)
+	}
+	sheet := out.String()
+
+	if blanks := strings.Count(sheet, worksheetBlank); blanks != 3 {
+		t.Errorf("expected 3 blanks, got %d:\n%s", blanks, sheet)
+	}
+	blanks, key, found := strings.Cut(sheet, "Answer key")
+	if !found {
+		t.Fatalf("worksheet should have an answer key:\n%s", sheet)
+	}
+	if !strings.Contains(blanks, " 3. "+worksheetBlank) || !strings.Contains(blanks, "You read it") {
+		t.Errorf("blanks should be numbered, with the definition as a hint:\n%s", blanks)
+	}
+	if strings.Contains(blanks, "Haus") {
+		t.Error("the words must only appear in the answer key")
+	}
+	if !strings.Contains(key, " 1. Haus\n 2. Buch\n 3. Schule") {
+		t.Errorf("answer key should list the words in order:\n%s", key)
+	}
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// buildNormalizer returns the function that prepares words for the
+// correctness check, chaining the comparison relaxations enabled in the
+// config; the diff shown to the learner always uses the original text
+// The order matters: quotes, whitespace and punctuation are cleaned up
+// first, so the word-based steps see clean words, and the letter-based
+// steps (ligatures, case, ß, diacritics) come last
+// Functions are values in Go, so the enabled steps are collected in a
+// slice once and the returned closure runs them one after the other
+// lang is the language of the word being checked, which can differ from
+// the list language (see WordEntry.Language); the article, number and ß
+// steps follow its rules
+func buildNormalizer(config *Config, lang string) func(string) string {
+	var steps []func(string) string
+	if config.TrimQuotes {
+		steps = append(steps, trimSurroundingQuotes)
+	}
+	if config.CollapseWhitespace {
+		steps = append(steps, normalizeWhitespace)
+	}
+	if config.IgnoreTrailingPunctuation {
+		steps = append(steps, trimTrailingPunct)
+	}
+	if config.OptionalLeadingArticle {
+		steps = append(steps, func(s string) string { return stripLeadingArticle(s, lang) })
+	}
+	if config.Numbers {
+		steps = append(steps, func(s string) string { return normalizeNumbers(s, lang) })
+	}
+	if config.FoldLigatures {
+		steps = append(steps, foldLigatures)
+	}
+	if config.IgnoreCase {
+		steps = append(steps, strings.ToLower)
+	}
+	if config.SzEquivalence && lang == "de" {
+		steps = append(steps, expandEszett)
+	}
+	if config.DiacriticsOptional {
+		steps = append(steps, stripDiacritics)
+	}
+
+	return func(s string) string {
+		for _, step := range steps {
+			s = step(s)
+		}
+		return s
+	}
+}
+
+// quotePairs maps each opening quote to the closing quotes that may end it
+// Typographic quotes differ between languages: English writes “Haus”,
+// German „Haus“, and French or Swiss German «Haus»
+var quotePairs = map[rune]string{
+	'"':  `"`,
+	'\'': "'",
+	'“':  "”",
+	'‘':  "’",
+	'„':  "“”",
+	'‚':  "‘’",
+	'«':  "»",
+	'»':  "«",
+}
+
+// trimSurroundingQuotes removes one pair of matching quotes around s
+// ("„Haus“" -> "Haus"); quotes inside the text or without their partner
+// are kept, so "Peter's" stays as it is
+func trimSurroundingQuotes(s string) string {
+	runes := []rune(s)
+	if len(runes) < 2 {
+		return s
+	}
+	closing, ok := quotePairs[runes[0]]
+	if !ok || !strings.ContainsRune(closing, runes[len(runes)-1]) {
+		return s
+	}
+	return string(runes[1 : len(runes)-1])
+}
+
+// trimTrailingPunct removes sentence-ending punctuation (".", "!", "?")
+// from the end of a phrase, e.g. "Der Hund bellt." -> "Der Hund bellt"
+func trimTrailingPunct(s string) string {
+	return strings.TrimRight(s, ".!?")
+}
+
+// normalizeWhitespace trims the ends and replaces every run of whitespace
+// inside the string with a single space ("the  fox " -> "the fox")
+// strings.Fields splits at any whitespace and drops the empty pieces
+func normalizeWhitespace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// ligatureReplacer writes ligatures as separate letters and turns the
+// typographic apostrophes into the straight one, which keyboards type
+var ligatureReplacer = strings.NewReplacer(
+	"œ", "oe", "Œ", "OE",
+	"æ", "ae", "Æ", "AE",
+	"’", "'", "ʼ", "'", "´", "'",
+)
+
+// foldLigatures replaces ligatures and apostrophe variants ("cœur" ->
+// "coeur", "l’école" -> "l'école"), so both spellings compare equal once
+// they went through it
+func foldLigatures(s string) string {
+	return ligatureReplacer.Replace(s)
+}
+
+// eszettReplacer spells out the German sharp s, including the rare capital
+// form used in all-caps writing ("STRAẞE")
+// A strings.Replacer is built once and can be reused safely
+var eszettReplacer = strings.NewReplacer("ß", "ss", "ẞ", "SS")
+
+// expandEszett replaces "ß" with "ss" ("Straße" -> "Strasse"), so both
+// spellings compare equal once they went through it
+func expandEszett(s string) string {
+	return eszettReplacer.Replace(s)
+}
+
+// leadingArticles lists the articles per language that may be left out
+// with OptionalLeadingArticle
+var leadingArticles = map[string][]string{
+	"en": {"the", "a", "an"},
+	"de": {"der", "die", "das"},
+}
+
+// stripLeadingArticle removes an article at the start of a phrase in the
+// given language ("the cat" -> "cat", "Das Haus" -> "Haus")
+// A lone article is kept, since there would be nothing left to check
+func stripLeadingArticle(s, lang string) string {
+	first, rest, found := strings.Cut(strings.TrimLeft(s, " "), " ")
+	if !found {
+		return s
+	}
+	for _, article := range leadingArticles[lang] {
+		// EqualFold compares case-insensitively, so "The" matches too
+		if strings.EqualFold(first, article) {
+			return strings.TrimLeft(rest, " ")
+		}
+	}
+	return s
+}
+
+// numberWords spells out the numbers 0 to 20 per language; the index of a
+// word is its value
+var numberWords = map[string][]string{
+	"en": {"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine", "ten",
+		"eleven", "twelve", "thirteen", "fourteen", "fifteen", "sixteen", "seventeen", "eighteen", "nineteen", "twenty"},
+	"de": {"null", "eins", "zwei", "drei", "vier", "fünf", "sechs", "sieben", "acht", "neun", "zehn",
+		"elf", "zwölf", "dreizehn", "vierzehn", "fünfzehn", "sechzehn", "siebzehn", "achtzehn", "neunzehn", "zwanzig"},
+}
+
+// normalizeNumbers replaces spelled-out numbers with numerals ("three
+// apples" -> "3 apples"), so both forms compare equal once they went
+// through it; only whole words in the given language are replaced
+func normalizeNumbers(s, lang string) string {
+	words := strings.Split(s, " ")
+	for i, word := range words {
+		for value, spelled := range numberWords[lang] {
+			if strings.EqualFold(word, spelled) {
+				words[i] = strconv.Itoa(value)
+				break
+			}
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// spellNumbers is the opposite of normalizeNumbers: it spells out the
+// numerals 0 to 20 ("3 apples" -> "three apples"), which text-to-speech
+// pronounces more clearly; other numbers are left as they are
+func spellNumbers(s, lang string) string {
+	spelled := numberWords[lang]
+	words := strings.Split(s, " ")
+	for i, word := range words {
+		// Atoi would also accept "+3", so only plain digits count
+		if strings.Trim(word, "0123456789") != "" {
+			continue
+		}
+		if value, err := strconv.Atoi(word); err == nil && value < len(spelled) {
+			words[i] = spelled[value]
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// diffTexts returns the input and target as the diff should compare them
+// With OptionalLeadingArticle the diff is against the forms without the
+// article, so a left-out article isn't marked as missing
+func diffTexts(input, target, lang string, config *Config) (string, string) {
+	if config.OptionalLeadingArticle {
+		return stripLeadingArticle(input, lang), stripLeadingArticle(target, lang)
+	}
+	return input, target
+}
+
+// answerMatches reports whether the input counts as a correct spelling of
+// target, a word in the language lang
+func answerMatches(input, target, lang string, config *Config) bool {
+	normalize := buildNormalizer(config, lang)
+	return normalize(input) == normalize(target)
+}
+
+// isCapitalizationOnly reports whether a wrong input has all the right
+// letters and only differs from target in upper and lower case ("haus")
+func isCapitalizationOnly(input, target, lang string, config *Config) bool {
+	normalize := buildNormalizer(config, lang)
+	input, target = normalize(input), normalize(target)
+	return input != target && strings.EqualFold(input, target)
+}
+
+// matchAnswer checks the input against several accepted answers
+// It returns the answer that matched, or otherwise the closest one (by
+// edit distance) so the diff shows the smallest set of changes; on a tie
+// the earlier answer wins, so the primary spelling is preferred
+func matchAnswer(input string, answers []string, lang string, config *Config) (string, bool) {
+	for _, answer := range answers {
+		if answerMatches(input, answer, lang, config) {
+			return answer, true
+		}
+	}
+	closest := answers[0]
+	best := editDistance(input, closest)
+	for _, answer := range answers[1:] {
+		if distance := editDistance(input, answer); distance < best {
+			closest, best = answer, distance
+		}
+	}
+	return closest, false
+}
+
+// stripDiacritics removes accents and umlaut dots from letters ("Höse" -> "Hose")
+// NFD decomposition splits "ö" into "o" plus a combining mark (category Mn),
+// the marks are removed, and NFC recomposes whatever is left
+func stripDiacritics(s string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	result, _, err := transform.String(t, s)
+	if err != nil {
+		return s
+	}
+	return result
+}
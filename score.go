@@ -0,0 +1,57 @@
+package main
+
+// Outcome classifies a submitted answer for scoring
+type Outcome int
+
+const (
+	outcomeFirstTry   Outcome = iota // Correct on the first attempt
+	outcomeEventually                // Correct after earlier mistakes
+	outcomeWithHint                  // Correct after the word was revealed
+	outcomeWrong                     // Misspelled
+)
+
+// Scoring maps outcomes to points (the optional `scoring` config section)
+// Wrong can be negative to penalize mistakes
+type Scoring struct {
+	FirstTry   int `yaml:"first_try"`
+	Eventually int `yaml:"eventually"`
+	WithHint   int `yaml:"with_hint"`
+	Wrong      int `yaml:"wrong"`
+}
+
+// defaultScoring returns the built-in point values
+func defaultScoring() Scoring {
+	return Scoring{
+		FirstTry:   10,
+		Eventually: 5,
+		WithHint:   2,
+		Wrong:      0,
+	}
+}
+
+// scoreFor returns the points an outcome is worth
+func scoreFor(outcome Outcome, cfg Scoring) int {
+	switch outcome {
+	case outcomeFirstTry:
+		return cfg.FirstTry
+	case outcomeEventually:
+		return cfg.Eventually
+	case outcomeWithHint:
+		return cfg.WithHint
+	case outcomeWrong:
+		return cfg.Wrong
+	}
+	return 0
+}
+
+// correctOutcome classifies a correct answer from the word's history:
+// how many answers were submitted for it and whether it was revealed
+func correctOutcome(attempts int, hinted bool) Outcome {
+	switch {
+	case attempts <= 1:
+		return outcomeFirstTry
+	case hinted:
+		return outcomeWithHint
+	}
+	return outcomeEventually
+}
@@ -0,0 +1,339 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+// answerSource provides the learner's answers to a session
+// The TUI reads keystrokes itself; other front ends (scripted answers,
+// plain stdin) implement this interface to drive RunSession
+type answerSource interface {
+	// NextAnswer returns the answer to the given prompt
+	// io.EOF signals that no more answers will come
+	NextAnswer(prompt string) (string, error)
+}
+
+// scriptedAnswers is an answerSource that replays a fixed list of answers
+type scriptedAnswers struct {
+	answers []string
+	next    int
+}
+
+// NextAnswer implements answerSource
+func (s *scriptedAnswers) NextAnswer(prompt string) (string, error) {
+	if s.next >= len(s.answers) {
+		return "", io.EOF
+	}
+	answer := s.answers[s.next]
+	s.next++
+	return answer, nil
+}
+
+// lineAnswers is an answerSource that reads one answer per line, e.g. from
+// stdin in plain mode; the end of the input ends the session
+type lineAnswers struct {
+	scanner *bufio.Scanner
+}
+
+// newLineAnswers reads answers line by line from r
+func newLineAnswers(r io.Reader) *lineAnswers {
+	return &lineAnswers{scanner: bufio.NewScanner(r)}
+}
+
+// NextAnswer implements answerSource
+// The prompt has already been printed by RunSession
+func (l *lineAnswers) NextAnswer(prompt string) (string, error) {
+	if !l.scanner.Scan() {
+		if err := l.scanner.Err(); err != nil {
+			return "", err
+		}
+		return "", io.EOF
+	}
+	return l.scanner.Text(), nil
+}
+
+// loadAnswers reads scripted answers from a file, one answer per line
+// The answers are given in the order the words appear in the config
+func loadAnswers(filename string) (*scriptedAnswers, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read answers file: %w", err)
+	}
+	// strings.Split keeps a trailing empty line; blank answers are
+	// skipped by RunSession anyway, just like an empty Enter in the TUI
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	return &scriptedAnswers{answers: lines}, nil
+}
+
+// WordResult is the outcome of practicing a single word
+type WordResult struct {
+	Word     string   `json:"word"`
+	Attempts int      `json:"attempts"`
+	Misses   int      `json:"misses"` // Wrong answers among the attempts
+	Answers  []string `json:"answers"`
+	Correct  bool     `json:"correct"` // Whether the word was eventually spelled correctly
+}
+
+// SessionResult carries the per-word outcomes and aggregate statistics of a session
+type SessionResult struct {
+	Words         []WordResult `json:"words"`
+	WordCount     int          `json:"word_count"`
+	TotalAttempts int          `json:"total_attempts"`
+	CorrectCount  int          `json:"correct_count"`
+	Accuracy      int          `json:"accuracy"` // Percentage of correct attempts
+	BestStreak    int          `json:"best_streak"`
+	Completed     bool         `json:"completed"` // False if answers ran out early
+}
+
+// checkSessionSupport returns an error if cfg uses an option that
+// RunSession doesn't implement, so a plain or JSON session never quietly
+// runs with different rules than the TUI
+func checkSessionSupport(cfg *Config) error {
+	switch {
+	case cfg.RecapMissed:
+		return errors.New("recap_missed needs the full-screen interface")
+	case cfg.MasteryLoop:
+		return errors.New("mastery_loop needs the full-screen interface")
+	case cfg.TimeLimit > 0:
+		return errors.New("time_limit needs the full-screen interface")
+	case cfg.TargetAccuracy > 0:
+		return errors.New("--target-accuracy needs the full-screen interface")
+	}
+	switch cfg.Mode {
+	case modeDictation, modeMemory:
+		return nil
+	}
+	return fmt.Errorf("mode %q needs the full-screen interface", cfg.Mode)
+}
+
+// RunSession practices the words of cfg in order without the TUI
+// Each word is spoken with speaker, answered through src, and the prompts
+// and feedback are translated with localizer and written to out. Incorrect words are requeued like in the
+// TUI. Running out of answers ends the session early without an error.
+func RunSession(cfg *Config, localizer *i18n.Localizer, speaker Speaker, src answerSource, out io.Writer) (SessionResult, error) {
+	words := cfg.wordList()
+	entries := cfg.entries()
+	result := SessionResult{WordCount: len(words)}
+
+	// Index results by word so requeued attempts update the same entry
+	resultIndex := make(map[string]int)
+	for _, word := range words {
+		if _, ok := resultIndex[word]; !ok {
+			resultIndex[word] = len(result.Words)
+			result.Words = append(result.Words, WordResult{Word: word, Answers: []string{}})
+		}
+	}
+
+	// Announce the session like the TUI does (SpeakIntro)
+	if cfg.SpeakIntro {
+		_ = speaker.Speak(tr(localizer, "PracticeInstructions", map[string]interface{}{"Count": len(words)}), cfg.Language, defaultRate)
+	}
+
+	streak := 0
+	retries := 0 // Immediate retries of the current word
+	for loop := 0; cfg.Loops == 0 || loop < cfg.Loops; loop++ {
+		queue := append([]string(nil), words...)
+		if loop > 0 {
+			queue = reorderWords(cfg, words)
+		}
+
+		for i := 0; i < len(queue); i++ {
+			word := queue[i]
+			wordResult := &result.Words[resultIndex[word]]
+			entry := entries[word]
+
+			// Memory mode shows the word instead of speaking it, like the
+			// TUI; a line-by-line prompt can't hide it again afterwards
+			language := entry.languageOr(cfg.Language)
+			if cfg.Mode == modeMemory {
+				memorize := tr(localizer, "MemorizePrompt", map[string]interface{}{"Number": i + 1})
+				fmt.Fprintln(out, memorize, entry.target(cfg.RequireArticle))
+			} else {
+				// Speaking errors should not stop the session
+				_ = speakPhrase(entry.spokenText(), language, jitteredRate(cfg.RateJitter), cfg.ChunkPhrases, speaker)
+			}
+
+			answer, err := nextNonEmptyAnswer(src, sessionPrompt(localizer, cfg.Mode, i+1), localizer, out)
+			if errors.Is(err, io.EOF) {
+				result.finish()
+				return result, nil
+			}
+			if err != nil {
+				return result, err
+			}
+
+			result.TotalAttempts++
+			wordResult.Attempts++
+			wordResult.Answers = append(wordResult.Answers, answer)
+
+			// Wrong answers are compared with the closest accepted spelling
+			target, correct := matchAnswer(answer, entry.acceptedAnswers(cfg.RequireArticle), language, cfg)
+			if correct {
+				result.CorrectCount++
+				wordResult.Correct = true
+				streak++
+				if streak > result.BestStreak {
+					result.BestStreak = streak
+				}
+				correctMsg := tr(localizer, "Correct")
+				fmt.Fprintln(out, correctMsg)
+				retries = 0
+				continue
+			}
+
+			streak = 0
+			wordResult.Misses++
+			incorrectMsg := tr(localizer, "IncorrectSpelling")
+			if cfg.CapitalizationHint && isCapitalizationOnly(answer, target, language, cfg) {
+				incorrectMsg = tr(localizer, "CapitalizationOnly")
+			}
+			fmt.Fprintln(out, incorrectMsg)
+			if cfg.SiblingHint && cfg.isSiblingWord(answer, word) {
+				fmt.Fprintln(out, tr(localizer, "SiblingWord"))
+			}
+			if cfg.Plain {
+				// Screen readers read the word letter by letter instead of a diff
+				correction := tr(localizer, "PlainCorrection", map[string]interface{}{
+					"Word":    target,
+					"Letters": spellOut(target),
+				})
+				fmt.Fprintln(out, correction)
+			} else if cfg.ShowDiff {
+				diffInput, diffTarget := diffTexts(answer, target, language, cfg)
+				fmt.Fprintln(out, formatWordDiff(diffInput, diffTarget, localizer, withRTL(cfg.isRTLFor(language)), withSeverity(severityFor(diffInput, diffTarget)), withTranspositions(cfg.MarkTranspositions)))
+			} else {
+				correctLabel := tr(localizer, "CorrectLabel")
+				fmt.Fprintln(out, correctLabel, target)
+			}
+
+			// Give up on the word after too many tries and spell it out instead
+			if cfg.MaxAttempts > 0 && wordResult.Misses >= cfg.MaxAttempts {
+				fmt.Fprintln(out, tr(localizer, "GiveUpSpelling"), spellOut(target))
+				_ = spellOutWord(target, language, speaker)
+				retries = 0
+				continue
+			}
+
+			// Single-pass sessions present every word exactly once
+			if cfg.SinglePass {
+				continue
+			}
+
+			// Practice the word again, right away or at the end of the queue
+			immediate := retryImmediately(cfg, retries)
+			queue = requeue(queue, i, word, immediate)
+			if immediate {
+				retries++
+			} else {
+				retries = 0
+			}
+		}
+	}
+
+	result.Completed = true
+	result.finish()
+	return result, nil
+}
+
+// writeJSONResult runs a non-interactive session and writes its result as JSON
+// The human-readable prompts and colored diffs are discarded, so nothing
+// but the JSON document (and no ANSI escape codes) reaches w
+func writeJSONResult(cfg *Config, localizer *i18n.Localizer, speaker Speaker, src answerSource, w io.Writer) error {
+	result, err := RunSession(cfg, localizer, speaker, src, io.Discard)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(result)
+}
+
+// printSummary writes the final statistics of a session as plain text
+func printSummary(result SessionResult, localizer *i18n.Localizer, out io.Writer) {
+	titleID := "PracticeComplete"
+	if !result.Completed {
+		titleID = "PracticeStopped"
+	}
+	fmt.Fprintln(out, tr(localizer, titleID))
+	fmt.Fprintln(out, tr(localizer, "WordsPracticed", map[string]interface{}{"Count": result.WordCount}))
+	fmt.Fprintln(out, tr(localizer, "TotalAttempts", map[string]interface{}{"Count": result.TotalAttempts}))
+	fmt.Fprintln(out, tr(localizer, "Accuracy", map[string]interface{}{"Percent": result.Accuracy}))
+}
+
+// spellOut spells a word letter by letter ("Haus" -> "H, a, u, s")
+// Words of a phrase are separated by semicolons so the pauses are audible
+func spellOut(s string) string {
+	words := strings.Fields(s)
+	spelled := make([]string, len(words))
+	for i, word := range words {
+		letters := make([]string, 0, len(word))
+		for _, r := range word {
+			letters = append(letters, string(r))
+		}
+		spelled[i] = strings.Join(letters, ", ")
+	}
+	return strings.Join(spelled, "; ")
+}
+
+// retryImmediately reports whether a misspelled word should be asked again
+// right away, given how often it has already been retried in a row
+func retryImmediately(cfg *Config, retries int) bool {
+	return cfg.RetryMode == retryImmediate && retries < cfg.ImmediateRetries
+}
+
+// requeue puts a misspelled word back into the queue
+// With immediate set it is inserted right after the current position,
+// otherwise it is appended to the end of the queue
+func requeue(queue []string, index int, word string, immediate bool) []string {
+	if !immediate {
+		return append(queue, word)
+	}
+
+	// slices can't insert in place, so rebuild: before + word + after
+	requeued := make([]string, 0, len(queue)+1)
+	requeued = append(requeued, queue[:index+1]...)
+	requeued = append(requeued, word)
+	return append(requeued, queue[index+1:]...)
+}
+
+// finish computes the aggregate statistics
+func (r *SessionResult) finish() {
+	if r.TotalAttempts > 0 {
+		r.Accuracy = r.CorrectCount * 100 / r.TotalAttempts
+	}
+}
+
+// sessionPrompt returns the localized prompt for the nth word in a mode
+func sessionPrompt(localizer *i18n.Localizer, mode string, number int) string {
+	promptID := "WordPrompt"
+	if mode == modeMemory {
+		promptID = "MemoryPrompt"
+	}
+	return tr(localizer, promptID, map[string]interface{}{"Number": number})
+}
+
+// nextNonEmptyAnswer asks src until it returns a non-blank answer
+// Blank answers get the same validation message as in the TUI
+func nextNonEmptyAnswer(src answerSource, prompt string, localizer *i18n.Localizer, out io.Writer) (string, error) {
+	for {
+		fmt.Fprintln(out, prompt)
+		answer, err := src.NextAnswer(prompt)
+		if err != nil {
+			return "", err
+		}
+		answer = strings.TrimSpace(answer)
+		if answer != "" {
+			return answer, nil
+		}
+		validationError := tr(localizer, "ValidationError")
+		fmt.Fprintln(out, "❌ "+validationError)
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// SessionState is the progress of a named session (--session), saved on
+// exit so a long list can be finished over several sittings
+// The queue starts with the word to ask next; completed words are gone
+type SessionState struct {
+	Words           []string       `json:"words"`     // Remaining queue
+	WordList        []string       `json:"word_list"` // All words of the session, for further loops
+	OriginalCount   int            `json:"original_count"`
+	LoopsCompleted  int            `json:"loops_completed"`
+	CorrectCount    int            `json:"correct_count"`
+	FirstTryCorrect int            `json:"first_try_correct"`
+	TotalAttempts   int            `json:"total_attempts"`
+	Score           int            `json:"score"`
+	BestStreak      int            `json:"best_streak"`
+	CorrectWords    []string       `json:"correct_words"`
+	Attempts        map[string]int `json:"attempts"`
+	Misses          map[string]int `json:"misses"`
+	Missed          []string       `json:"missed,omitempty"` // Words for the recap (RecapMissed)
+	Recapping       bool           `json:"recapping,omitempty"`
+	Mastered        []string       `json:"mastered,omitempty"` // Words spelled correctly (MasteryLoop)
+	MasteryRounds   int            `json:"mastery_rounds,omitempty"`
+}
+
+// sessionStatePath returns the state file of a named session
+// Session files live in the user's config directory (e.g.
+// ~/.config/dictation/sessions on Linux), so they work from any folder
+func sessionStatePath(name string) (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("no directory for session files: %w", err)
+	}
+	return filepath.Join(dir, "dictation", "sessions", audioFileName(name)+".json"), nil
+}
+
+// saveSessionState writes the state to path, creating its directory
+func saveSessionState(path string, state SessionState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create session directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+	return nil
+}
+
+// loadSessionState reads a state saved by saveSessionState
+// A session that was never saved gives an error that wraps
+// fs.ErrNotExist, so callers can tell a new session from a broken file
+func loadSessionState(path string) (SessionState, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return SessionState{}, fmt.Errorf("failed to read session: %w", err)
+	}
+	var state SessionState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return SessionState{}, fmt.Errorf("invalid session file %s: %w", path, err)
+	}
+	return state, nil
+}
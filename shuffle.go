@@ -1,10 +1,28 @@
 package main
 
 import (
+	"fmt"
+	"log"
 	"math/rand"
+	"regexp"
+	"strings"
 	"time"
 )
 
+// rng is the random number generator used for shuffling and sampling
+// It is not safe for concurrent use, so it must not be used in tea.Cmds
+// It is seeded with the current time to get different orders each run,
+// or with a fixed value via --seed for reproducible sessions
+var rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+
+// seedRandom replaces the generators with ones using a fixed seed
+// rng is only used on the main goroutine; the voices have their own
+// generator (see voiceRNG)
+func seedRandom(seed int64) {
+	rng = rand.New(rand.NewSource(seed))
+	seedVoices(seed)
+}
+
 // shuffleWords shuffles a slice of words using Fisher-Yates algorithm
 // This function takes a slice (Go's dynamic array type) and returns
 // a new shuffled slice without modifying the original.
@@ -14,16 +32,244 @@ func shuffleWords(words []string) []string {
 	shuffled := make([]string, len(words))
 	copy(shuffled, words)
 
-	// Create a new random number generator seeded with current time
-	// This ensures different shuffles each run
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
-	
 	// Fisher-Yates shuffle: iterate backwards, swap each element
 	// with a random element from the unshuffled portion
 	for i := len(shuffled) - 1; i > 0; i-- {
-		j := r.Intn(i + 1)  // Random index from 0 to i
+		j := rng.Intn(i + 1)  // Random index from 0 to i
 		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]  // Swap
 	}
 
 	return shuffled
 }
+
+// scrambleLetters shuffles the letters of each word of a phrase, keeping
+// the spaces in place ("Haus" -> "suaH"); the same seed gives the same
+// scramble. When the shuffle happens to restore the word, the letters are
+// rotated by one, so the scramble differs unless all letters are the same
+func scrambleLetters(word string, seed int64) string {
+	shuffler := rand.New(rand.NewSource(seed))
+	parts := strings.Split(word, " ")
+	for i, part := range parts {
+		letters := []rune(part)
+		shuffler.Shuffle(len(letters), func(a, b int) {
+			letters[a], letters[b] = letters[b], letters[a]
+		})
+		if string(letters) == part && len(letters) > 1 {
+			letters = append(letters[1:], letters[0])
+		}
+		parts[i] = string(letters)
+	}
+	return strings.Join(parts, " ")
+}
+
+// generateDistractors returns n wrong answers for the choose mode: typos
+// of word first (a swapped, doubled or missing letter), which test the
+// spelling, then other words from pool; there may be fewer than n when
+// neither gives enough different candidates
+// Every distractor differs from word and from the others, and accepted
+// rejects candidates that would count as correct, e.g. an accepted
+// spelling or a typo that the normalization rules take back
+func generateDistractors(word string, pool []string, n int, accepted func(string) bool) []string {
+	seen := map[string]bool{word: true}
+	var distractors []string
+	add := func(candidate string) {
+		if len(distractors) < n && candidate != "" && !seen[candidate] && !accepted(candidate) {
+			seen[candidate] = true
+			distractors = append(distractors, candidate)
+		}
+	}
+	
+	// A few more tries than needed, since some typos give the same text
+	// (swapping "ss" changes nothing)
+	letters := []rune(word)
+	for try := 0; try < 4*n && len(distractors) < n && len(letters) > 1; try++ {
+		typo := append([]rune(nil), letters...)
+		i := rng.Intn(len(typo) - 1)
+		switch rng.Intn(3) {
+		case 0: // Swap two neighbors
+			typo[i], typo[i+1] = typo[i+1], typo[i]
+		case 1: // Double a letter
+			// A new slice, since appending to typo[:i+1] would overwrite
+			// the letters that are still to be copied
+			typo = append(append([]rune(nil), typo[:i+1]...), typo[i:]...)
+		case 2: // Leave out a letter
+			typo = append(typo[:i+1], typo[i+2:]...)
+		}
+		add(string(typo))
+	}
+	for _, other := range shuffleWords(pool) {
+		add(other)
+	}
+	return distractors
+}
+
+// sampleWords returns n randomly chosen words in random order
+// If n is zero, negative or larger than the list, all words are returned shuffled
+func sampleWords(words []string, n int) []string {
+	shuffled := shuffleWords(words)
+	if n <= 0 || n >= len(shuffled) {
+		return shuffled
+	}
+	// After shuffling, the first n words are a uniform random sample
+	return shuffled[:n]
+}
+
+// orderWords returns the words for a session: a random sample of n words
+// or, when shuffle is false, the first n words in their original order
+// so that consecutive runs can be compared word by word
+func orderWords(words []string, n int, shuffle bool) []string {
+	if shuffle {
+		return sampleWords(words, n)
+	}
+	ordered := append([]string(nil), words...)
+	if n <= 0 || n >= len(ordered) {
+		return ordered
+	}
+	return ordered[:n]
+}
+
+// bucketedShuffle groups the entries by difficulty and shuffles each
+// group, then puts the groups in order: all easy words, then medium, then
+// hard, for a gentle ramp through the list
+func bucketedShuffle(entries []WordEntry) []WordEntry {
+	buckets := make([][]WordEntry, len(difficultyLevels))
+	for _, entry := range entries {
+		rank := entry.difficultyRank()
+		buckets[rank] = append(buckets[rank], entry)
+	}
+	
+	ordered := make([]WordEntry, 0, len(entries))
+	for _, bucket := range buckets {
+		// rng.Shuffle is Fisher-Yates like shuffleWords, for any slice type
+		rng.Shuffle(len(bucket), func(i, j int) {
+			bucket[i], bucket[j] = bucket[j], bucket[i]
+		})
+		ordered = append(ordered, bucket...)
+	}
+	return ordered
+}
+
+// reorderWords returns a fresh order of the words for another loop or a
+// restart: ramped by difficulty with --ramp, otherwise shuffled (unless
+// --no-shuffle keeps the config order)
+func reorderWords(config *Config, words []string) []string {
+	if !config.Ramp {
+		return orderWords(words, 0, !config.NoShuffle)
+	}
+	entries := config.entries()
+	selected := make([]WordEntry, len(words))
+	for i, word := range words {
+		selected[i] = entries[word]
+	}
+	ramped := bucketedShuffle(selected)
+	ordered := make([]string, len(ramped))
+	for i, entry := range ramped {
+		ordered[i] = entry.Word
+	}
+	return ordered
+}
+
+// weightedSample draws n distinct entries, each with a probability
+// proportional to its weight, in the order they were drawn
+// If n is zero, negative or larger than the list, all entries are drawn
+func weightedSample(words []WordEntry, n int) []WordEntry {
+	if n <= 0 || n > len(words) {
+		n = len(words)
+	}
+	
+	// Draw without replacement: pick one entry by weight, remove it, repeat
+	pool := append([]WordEntry(nil), words...)
+	sample := make([]WordEntry, 0, n)
+	for len(sample) < n {
+		total := 0.0
+		for _, entry := range pool {
+			total += entry.weight()
+		}
+		
+		// Walk the pool until the random point falls into an entry's share
+		point := rng.Float64() * total
+		chosen := len(pool) - 1 // Guards against rounding at the very end
+		for i, entry := range pool {
+			point -= entry.weight()
+			if point < 0 {
+				chosen = i
+				break
+			}
+		}
+		
+		sample = append(sample, pool[chosen])
+		pool = append(pool[:chosen], pool[chosen+1:]...)
+	}
+	return sample
+}
+
+// sessionWords picks the words for a session from the config
+// A shuffled session draws a weighted random sample (all words if n is 0),
+// otherwise the first n words are practiced in config order
+func sessionWords(config *Config, n int) []string {
+	if config.NoShuffle {
+		return orderWords(config.wordList(), n, false)
+	}
+	sample := weightedSample(config.Words, n)
+	words := make([]string, len(sample))
+	for i, entry := range sample {
+		words[i] = entry.Word
+	}
+	return words
+}
+
+// applyFocus moves the focus words to the front, in the order given, and
+// keeps the (shuffled) order of the other words behind them
+// Focus words that are not in the list are ignored with a warning
+func applyFocus(words []string, focus []string) []string {
+	if len(focus) == 0 {
+		return words
+	}
+	
+	inList := make(map[string]bool, len(words))
+	for _, word := range words {
+		inList[word] = true
+	}
+	
+	ordered := make([]string, 0, len(words))
+	focused := make(map[string]bool, len(focus))
+	for _, word := range focus {
+		if !inList[word] {
+			log.Printf("Warning: focus word %q is not in the word list, ignoring it", word)
+			continue
+		}
+		if !focused[word] {
+			focused[word] = true
+			ordered = append(ordered, word)
+		}
+	}
+	for _, word := range words {
+		if !focused[word] {
+			ordered = append(ordered, word)
+		}
+	}
+	return ordered
+}
+
+// filterWordsByPattern keeps the words that match a regular expression,
+// e.g. "sch" or "^qu" to drill a spelling pattern (phonics)
+// Matching is case-sensitive unless the pattern starts with (?i)
+func filterWordsByPattern(words []string, pattern string) ([]string, error) {
+	// regexp.Compile returns an error for invalid patterns instead of
+	// panicking like regexp.MustCompile
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --pattern %q: %w", pattern, err)
+	}
+	
+	var matching []string
+	for _, word := range words {
+		if re.MatchString(word) {
+			matching = append(matching, word)
+		}
+	}
+	if len(matching) == 0 {
+		return nil, fmt.Errorf("no words in the list match --pattern %q", pattern)
+	}
+	return matching, nil
+}
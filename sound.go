@@ -0,0 +1,73 @@
+package main
+
+import (
+	"embed"
+	"os"
+	"path/filepath"
+)
+
+// soundFiles holds the short feedback sounds bundled into the binary
+// The go:embed directive copies the files in at compile time, so the
+// sounds work without installing anything next to the executable
+//
+//go:embed sounds/*.wav
+var soundFiles embed.FS
+
+// Names of the bundled sound effects (files in sounds/ without .wav)
+const (
+	soundCorrect   = "ding"
+	soundIncorrect = "buzz"
+)
+
+// soundPlayers lists the command-line audio players we know, in order of
+// preference: afplay ships with macOS, aplay with most Linux distributions
+var soundPlayers = []string{"afplay", "aplay"}
+
+// detectSoundPlayer returns the first available audio player, or "" if none
+func detectSoundPlayer() string {
+	for _, player := range soundPlayers {
+		if _, err := lookPath(player); err == nil {
+			return player
+		}
+	}
+	return ""
+}
+
+// playSound plays a bundled sound effect by name
+// It is a no-op when no audio player is installed, since sound effects
+// are an optional extra and must never interrupt the practice
+func playSound(name string) error {
+	player := detectSoundPlayer()
+	if player == "" {
+		return nil
+	}
+
+	data, err := soundFiles.ReadFile("sounds/" + name + ".wav")
+	if err != nil {
+		return err
+	}
+
+	// The players need a real file, so copy the embedded bytes to a
+	// temporary file and remove it again once playback has finished
+	dir, err := os.MkdirTemp("", "dictation-sound")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, name+".wav")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return err
+	}
+
+	return playAudioFile(player, path)
+}
+
+// playAudioFile plays an audio file with the given player command
+func playAudioFile(player, path string) error {
+	if player == "aplay" {
+		// -q suppresses aplay's status output, which would garble the TUI
+		return runCommand(player, "-q", path)
+	}
+	return runCommand(player, path)
+}
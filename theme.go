@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ThemeConfig represents the optional `theme` section of the config file
+// Colors are lipgloss color codes: ANSI numbers ("0"-"255") or hex ("#ff8800")
+// Empty values keep the built-in defaults
+type ThemeConfig struct {
+	Cursor      string `yaml:"cursor"`      // Character drawn after the typed text
+	Placeholder string `yaml:"placeholder"` // Color of the placeholder text
+	Correct     string `yaml:"correct"`     // Color of matching characters in the diff
+	Wrong       string `yaml:"wrong"`       // Color of differing characters in the diff
+	NearMiss    string `yaml:"near_miss"`   // Color of differing characters in almost correct answers
+}
+
+// styleSet bundles the styles that can be customized through the theme
+// It is built once at startup and handed to the TUI and the diff renderer
+type styleSet struct {
+	cursor       string
+	placeholder  lipgloss.Style
+	correctChar  lipgloss.Style
+	wrongChar    lipgloss.Style
+	nearMissChar lipgloss.Style
+}
+
+// defaultStyleSet returns the built-in styles
+func defaultStyleSet() styleSet {
+	return styleSet{
+		cursor:       "█",
+		placeholder:  lipgloss.NewStyle().Foreground(lipgloss.Color("8")), // Gray
+		correctChar:  correctCharStyle,
+		wrongChar:    wrongCharStyle,
+		nearMissChar: nearMissCharStyle,
+	}
+}
+
+// newStyleSet applies the theme settings on top of the default styles
+// The theme is expected to be validated already (see validateTheme)
+func newStyleSet(theme ThemeConfig) styleSet {
+	styles := defaultStyleSet()
+	if theme.Cursor != "" {
+		styles.cursor = theme.Cursor
+	}
+	if theme.Placeholder != "" {
+		styles.placeholder = styles.placeholder.Foreground(lipgloss.Color(theme.Placeholder))
+	}
+	if theme.Correct != "" {
+		styles.correctChar = styles.correctChar.Foreground(lipgloss.Color(theme.Correct))
+	}
+	if theme.Wrong != "" {
+		styles.wrongChar = styles.wrongChar.Foreground(lipgloss.Color(theme.Wrong))
+	}
+	if theme.NearMiss != "" {
+		styles.nearMissChar = styles.nearMissChar.Foreground(lipgloss.Color(theme.NearMiss))
+	}
+	return styles
+}
+
+// validateTheme checks that all configured colors are valid lipgloss colors
+func validateTheme(theme ThemeConfig) error {
+	colors := []struct {
+		name  string
+		value string
+	}{
+		{"placeholder", theme.Placeholder},
+		{"correct", theme.Correct},
+		{"wrong", theme.Wrong},
+		{"near_miss", theme.NearMiss},
+	}
+	for _, color := range colors {
+		if color.value != "" && !isValidColor(color.value) {
+			return fmt.Errorf("invalid theme color for %q: %q (use an ANSI code 0-255 or a hex color like #ff8800)", color.name, color.value)
+		}
+	}
+	return nil
+}
+
+// isValidColor reports whether s is an ANSI color code (0-255) or a hex color (#rgb or #rrggbb)
+func isValidColor(s string) bool {
+	if strings.HasPrefix(s, "#") {
+		hex := s[1:]
+		if len(hex) != 3 && len(hex) != 6 {
+			return false
+		}
+		_, err := strconv.ParseUint(hex, 16, 32)
+		return err == nil
+	}
+	code, err := strconv.Atoi(s)
+	return err == nil && code >= 0 && code <= 255
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// transcriptEntry is one answer in the transcript pane (ShowTranscript)
+type transcriptEntry struct {
+	word    string // The word that was asked
+	input   string // What the learner typed
+	correct bool
+}
+
+// transcriptWidth is the width of the transcript pane including its border
+// The pane is only shown when the rest still has the minimum width
+const transcriptWidth = 32
+
+// transcriptContentWidth is the room for text inside the border and padding
+const transcriptContentWidth = transcriptWidth - 4
+
+var transcriptStyle = lipgloss.NewStyle().
+	Border(lipgloss.RoundedBorder()).
+	BorderForeground(lipgloss.Color("8")). // Gray, to stay in the background
+	Padding(0, 1).
+	Width(transcriptWidth - 2) // Width excludes the border
+
+// showsTranscript reports whether the transcript pane fits next to the
+// practice area
+func (m appModel) showsTranscript() bool {
+	return m.config.ShowTranscript && m.width >= minTerminalWidth+transcriptWidth
+}
+
+// mainWidth is the width left for the practice area
+func (m appModel) mainWidth() int {
+	if m.showsTranscript() {
+		return m.width - transcriptWidth
+	}
+	return m.width
+}
+
+// withTranscript puts the transcript pane to the right of the practice
+// area, if it is shown
+// JoinHorizontal lines up two blocks of text side by side, top-aligned
+func (m appModel) withTranscript(main string, height int) string {
+	if !m.showsTranscript() {
+		return main
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, main, m.renderTranscript(height))
+}
+
+// renderTranscript renders the pane with the latest answers, each marked
+// as correct or wrong; when there are more answers than lines, the oldest
+// scroll out at the top so the newest answer is always visible
+func (m appModel) renderTranscript(height int) string {
+	lines := make([]string, 0, len(m.transcript))
+	for _, entry := range m.transcript {
+		// Lines are cut to the pane instead of wrapping, which would push
+		// the newest answers out at the bottom
+		if entry.correct {
+			lines = append(lines, successStyle.Render(cutRunes("✔ "+entry.input, transcriptContentWidth)))
+		} else {
+			lines = append(lines, errorStyle.Render(cutRunes("✘ "+entry.input+" → "+entry.word, transcriptContentWidth)))
+		}
+	}
+
+	// One line each for the border at the top and bottom and the title
+	visible := height - 3
+	if visible < 1 {
+		visible = 1
+	}
+	if len(lines) > visible {
+		lines = lines[len(lines)-visible:]
+	}
+
+	title := labelStyle.Render(tr(m.localizer, "TranscriptTitle"))
+	content := title + "\n" + strings.Join(lines, "\n")
+	return transcriptStyle.Height(height - 2).MaxHeight(height).Render(content)
+}
+
+// cutRunes shortens s to at most n runes, marking the cut with "…"
+func cutRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
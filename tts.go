@@ -1,16 +1,218 @@
 package main
 
 import (
+	"fmt"
+	"math/rand"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
 )
 
+// Speech rates in words per minute
+const (
+	defaultRate = 180 // Normal dictation speed
+	slowRate    = 110 // Slow replay for hard words
+)
+
+// Speaker speaks text aloud in the voice for a language
+// Interfaces in Go are satisfied implicitly: any type with a matching
+// Speak method is a Speaker, which lets tests swap in a fake engine
+type Speaker interface {
+	Speak(text, langCode string, rate int) error
+}
+
+// audioExporter writes synthesized speech to a file instead of the speakers
+// FileExtension is the extension of the written format, e.g. ".aiff"
+type audioExporter interface {
+	SpeakToFile(text, langCode, path string) error
+	FileExtension() string
+}
+
+// synthesizer is a Speaker that can also write speech to an audio file,
+// which lets cachingSpeaker synthesize each word only once
+type synthesizer interface {
+	Speaker
+	Synthesize(text, langCode, path string, rate int) error
+}
+
+// sayEngine is the Speaker backed by macOS's native 'say' command
+// voices holds the voices configured for each language, if any
+type sayEngine struct {
+	voices  map[string]voiceList
+	device  string  // Audio output device ID for 'say -a' (empty = default)
+	volume  float64 // Speech volume from 0 to 1 (0 = system volume)
+	preRoll bool    // Start with a short silence (PreRollSilence)
+}
+
+// Speak implements Speaker
+func (e sayEngine) Speak(text, langCode string, rate int) error {
+	return speakWord(e.withCommands(text), getVoiceForLanguage(langCode, e.voices), rate, e.device)
+}
+
+// preRollMillis is the silence before the text with PreRollSilence, long
+// enough for a sleeping audio device to wake up before the first syllable
+const preRollMillis = 250
+
+// withCommands prepends the embedded 'say' commands for the volume and the
+// pre-roll silence
+// [[volm 0.5]] and [[slnc 250]] are read by 'say' as instructions, not
+// spoken aloud
+func (e sayEngine) withCommands(text string) string {
+	if e.preRoll {
+		text = "[[slnc " + strconv.Itoa(preRollMillis) + "]] " + text
+	}
+	if e.volume != 0 {
+		text = "[[volm " + strconv.FormatFloat(e.volume, 'f', -1, 64) + "]] " + text
+	}
+	return text
+}
+
+// checkAudioDevice reports whether 'say' can use the output device
+// It says nothing on the device, which fails for unknown device IDs
+func checkAudioDevice(device string) error {
+	if err := runCommand("say", "-a", device, ""); err != nil {
+		return fmt.Errorf("audio device %q is not available (list devices with say -a '?'): %w", device, err)
+	}
+	return nil
+}
+
+// SpeakToFile implements audioExporter using 'say -o'
+func (e sayEngine) SpeakToFile(text, langCode, path string) error {
+	return e.Synthesize(text, langCode, path, defaultRate)
+}
+
+// Synthesize implements synthesizer using 'say -o' at the given rate
+func (e sayEngine) Synthesize(text, langCode, path string, wpm int) error {
+	voice := getVoiceForLanguage(langCode, e.voices)
+	rate := strconv.Itoa(wpm)
+	text = e.withCommands(text)
+	if voice != "" {
+		if err := runCommand("say", "-v", voice, "-r", rate, "-o", path, text); err == nil {
+			return nil
+		}
+	}
+	// Fallback to default system voice
+	return runCommand("say", "-r", rate, "-o", path, text)
+}
+
+// FileExtension implements audioExporter; 'say -o' writes AIFF files
+func (sayEngine) FileExtension() string {
+	return ".aiff"
+}
+
+// espeakEngine is the Speaker backed by the cross-platform 'espeak' command
+type espeakEngine struct{}
+
+// Speak implements Speaker
+// espeak selects voices by language code and takes the rate via -s
+func (espeakEngine) Speak(text, langCode string, rate int) error {
+	return runCommand("espeak", "-v", langCode, "-s", strconv.Itoa(rate), text)
+}
+
+// SpeakToFile implements audioExporter using 'espeak -w'
+func (espeakEngine) SpeakToFile(text, langCode, path string) error {
+	return runCommand("espeak", "-v", langCode, "-s", strconv.Itoa(defaultRate), "-w", path, text)
+}
+
+// FileExtension implements audioExporter; 'espeak -w' writes WAV files
+func (espeakEngine) FileExtension() string {
+	return ".wav"
+}
+
+// noneEngine is a silent Speaker, useful for debugging the TUI
+type noneEngine struct{}
+
+// Speak implements Speaker without producing any sound
+func (noneEngine) Speak(text, langCode string, rate int) error {
+	return nil
+}
+
+// ttsEngine returns the Speaker for an engine name ("say", "espeak", "none")
+// An empty name auto-detects the first engine available on the PATH
+func ttsEngine(name string) (Speaker, error) {
+	switch name {
+	case "":
+		return detectTTSEngine(), nil
+	case "say":
+		return sayEngine{}, requireBinary("say")
+	case "espeak":
+		return espeakEngine{}, requireBinary("espeak")
+	case "none":
+		return noneEngine{}, nil
+	}
+	return nil, fmt.Errorf("unknown TTS engine %q (use say, espeak or none)", name)
+}
+
+// requireBinary checks that a TTS command is installed
+// Without this check a missing binary only shows up as words that are
+// silently never spoken, which is confusing for learners
+func requireBinary(name string) error {
+	if _, err := lookPath(name); err != nil {
+		return fmt.Errorf("TTS command %q was not found on your PATH; install it or use --tts-engine none to practice without audio", name)
+	}
+	return nil
+}
+
+// detectTTSEngine prefers macOS 'say', then 'espeak', and falls back to silence
+func detectTTSEngine() Speaker {
+	if _, err := lookPath("say"); err == nil {
+		return sayEngine{}
+	}
+	if _, err := lookPath("espeak"); err == nil {
+		return espeakEngine{}
+	}
+	return noneEngine{}
+}
+
+// lookPath searches the PATH for an executable
+// It is a variable so tests can simulate installed or missing engines
+var lookPath = exec.LookPath
+
+// runCommand runs an external command and waits for it to finish
+// It is a variable so tests can replace it and inspect the arguments
+var runCommand = func(name string, args ...string) error {
+	return exec.Command(name, args...).Run()
+}
+
+// voiceRNG picks the voices, guarded by voiceMu
+// Words are spoken in background commands, which may run at the same time
+// as each other and as the shuffling on the main goroutine, so the voices
+// don't share rng, which is not safe for concurrent use
+var (
+	voiceRNG = rand.New(rand.NewSource(time.Now().UnixNano()))
+	voiceMu  sync.Mutex
+)
+
+// seedVoices replaces the voice generator with one using a fixed seed
+func seedVoices(seed int64) {
+	voiceMu.Lock()
+	defer voiceMu.Unlock()
+	
+	voiceRNG = rand.New(rand.NewSource(seed))
+}
+
 // getVoiceForLanguage returns the macOS TTS voice name for a language code
 // Maps language codes to appropriate voices for better pronunciation
-func getVoiceForLanguage(langCode string) string {
+// Configured voices take precedence; with several of them, a random one
+// is chosen each time, so learners don't get used to one voice's quirks
+func getVoiceForLanguage(langCode string, configured map[string]voiceList) string {
+	if choices := configured[langCode]; len(choices) > 0 {
+		voiceMu.Lock()
+		defer voiceMu.Unlock()
+		return choices[voiceRNG.Intn(len(choices))]
+	}
+	
 	voices := map[string]string{
 		"de": "Anna",    // German voice
 		"en": "Alex",    // English voice (US)
 		"fr": "Thomas",  // French voice (for future use)
+		"ar": "Maged",   // Arabic voice
+		"he": "Carmit",  // Hebrew voice
 	}
 
 	if voice, ok := voices[langCode]; ok {
@@ -21,25 +223,156 @@ func getVoiceForLanguage(langCode string) string {
 }
 
 // speakWord uses macOS's native 'say' command to speak a word
-// Uses the appropriate voice for the specified language
-func speakWord(word string, langCode string) error {
-	voice := getVoiceForLanguage(langCode)
+// Uses the given voice (or the system voice if empty) at the given rate,
+// on the given output device (or the default one if empty)
+func speakWord(word string, voice string, rate int, device string) error {
+	wpm := strconv.Itoa(rate)
+	
+	// -a selects the audio device; it goes in front of the other options
+	var output []string
+	if device != "" {
+		output = []string{"-a", device}
+	}
 	
-	var cmd *exec.Cmd
+	var err error
 	if voice != "" {
 		// Use language-specific voice
 		// -v specifies the voice, -r sets speech rate (words per minute)
-		cmd = exec.Command("say", "-v", voice, "-r", "180", word)
+		err = runCommand("say", append(output, "-v", voice, "-r", wpm, word)...)
 	} else {
 		// Fallback to default system voice
-		cmd = exec.Command("say", "-r", "180", word)
+		err = runCommand("say", append(output, "-r", wpm, word)...)
 	}
 	
-	// cmd.Run() executes the command and waits for completion
-	if err := cmd.Run(); err != nil {
+	if err != nil {
 		// If voice-specific command fails, try default voice
-		cmd := exec.Command("say", "-r", "180", word)
-		return cmd.Run()
+		return runCommand("say", append(output, "-r", wpm, word)...)
+	}
+	return nil
+}
+
+// previewPause is the silence between words when previewing the list
+// It is a variable so tests don't have to wait
+var previewPause = 700 * time.Millisecond
+
+// previewWords speaks every word once in order with a short pause after each,
+// like a teacher reading the whole list aloud before the dictation
+// Speaking errors are ignored so that a missing voice doesn't stop the preview
+func previewWords(words []string, lang string, speaker Speaker) {
+	for _, word := range words {
+		_ = speaker.Speak(word, lang, defaultRate)
+		time.Sleep(previewPause)
+	}
+}
+
+// jitteredRate returns the normal rate, varied by up to jitter words per
+// minute in either direction (RateJitter), so learners can't rely on a
+// fixed cadence; it uses rng, so --seed makes the rates reproducible
+func jitteredRate(jitter int) int {
+	if jitter <= 0 {
+		return defaultRate
+	}
+	// Intn(2*jitter+1) is 0 to 2*jitter, so the offset is -jitter to jitter
+	return defaultRate + rng.Intn(2*jitter+1) - jitter
+}
+
+// phraseChunkPause is the gap between the words of a chunked phrase
+// It is a variable so tests don't have to wait
+var phraseChunkPause = 400 * time.Millisecond
+
+// speakPhrase speaks a word or phrase at the given rate
+// With chunked set, a phrase is spoken word by word with a short gap in
+// between, which gives learners time to write long phrases down
+func speakPhrase(phrase, lang string, rate int, chunked bool, speaker Speaker) error {
+	tokens := strings.Fields(phrase)
+	if !chunked || len(tokens) < 2 {
+		return speaker.Speak(phrase, lang, rate)
+	}
+	
+	for i, token := range tokens {
+		if i > 0 {
+			time.Sleep(phraseChunkPause)
+		}
+		if err := speaker.Speak(token, lang, rate); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// spellLetterPause is the gap after each letter when spelling a word out
+// It is a variable so tests don't have to wait
+var spellLetterPause = 350 * time.Millisecond
+
+// speakLetter speaks a single letter slowly, followed by a short pause
+// Spaces between the words of a phrase are only a pause
+func speakLetter(letter rune, lang string, speaker Speaker) error {
+	var err error
+	if !unicode.IsSpace(letter) {
+		err = speaker.Speak(string(letter), lang, slowRate)
+	}
+	time.Sleep(spellLetterPause)
+	return err
+}
+
+// spellOutWord speaks a word letter by letter ("H", "a", "u", "s"), the way
+// a teacher spells a word the learner couldn't get right
+func spellOutWord(word, lang string, speaker Speaker) error {
+	for _, letter := range word {
+		if err := speakLetter(letter, lang, speaker); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exportAudio writes one audio file per word into dir instead of speaking aloud
+// The files can be played on devices without text-to-speech support.
+// Each word is spoken like in practice (article, pronunciation) in its own
+// language, where langCode is the language of the word list.
+func exportAudio(words []WordEntry, langCode, dir string, exporter audioExporter) error {
+	// MkdirAll creates the directory and any missing parents
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create audio directory: %w", err)
+	}
+	
+	// Words like "Haus" and "haus" would get the same file on
+	// case-insensitive file systems, so later ones are numbered
+	used := make(map[string]bool)
+	for _, entry := range words {
+		base := audioFileName(entry.Word)
+		name := base
+		for n := 2; used[strings.ToLower(name)]; n++ {
+			name = base + "_" + strconv.Itoa(n)
+		}
+		used[strings.ToLower(name)] = true
+		
+		path := filepath.Join(dir, name+exporter.FileExtension())
+		if err := exporter.SpeakToFile(entry.spokenText(), entry.languageOr(langCode), path); err != nil {
+			return fmt.Errorf("failed to export audio for %q: %w", entry.Word, err)
+		}
 	}
 	return nil
 }
+
+// audioFileName turns a word into a safe file name
+// Path separators, whitespace and characters that are invalid on common
+// file systems are replaced with underscores; letters like ä or ß are kept
+func audioFileName(word string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r == '/' || r == '\\' || r == ' ' || r == '\t':
+			return '_'
+		case strings.ContainsRune(`:*?"<>|`, r):
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(word))
+	
+	// Avoid hidden files and names like ".." that refer to directories
+	name = strings.TrimLeft(name, ".")
+	if name == "" {
+		return "word"
+	}
+	return name
+}
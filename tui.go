@@ -1,7 +1,11 @@
 package main
 
 import (
+	"runtime"
+	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
@@ -23,6 +27,38 @@ type dialogType int
 const (
 	dialogCorrect dialogType = iota
 	dialogIncorrect
+	dialogMilestone // Correct answer that reached a streak milestone
+)
+
+// feedbackFlash is the color the title bar flashes in after an answer
+// (see Config.FlashFeedback)
+type feedbackFlash int
+
+const (
+	flashNone feedbackFlash = iota
+	flashGreen // Correct answer
+	flashRed   // Wrong answer
+)
+
+// streakMilestones are the streak lengths that trigger a celebration dialog
+// Kept as a package-level slice so the thresholds are easy to adjust
+var streakMilestones = []int{5, 10, 20}
+
+// isStreakMilestone reports whether a streak length is one of the milestones
+func isStreakMilestone(streak int) bool {
+	for _, milestone := range streakMilestones {
+		if streak == milestone {
+			return true
+		}
+	}
+	return false
+}
+
+// Minimum terminal size needed to render the dialog (Width(60) plus borders)
+// and the title bar without garbling the layout
+const (
+	minTerminalWidth  = 62
+	minTerminalHeight = 10
 )
 
 // appModel is the main TUI model for the dictation practice app
@@ -32,28 +68,116 @@ type appModel struct {
 	ready        bool
 	width        int
 	height       int
+	tooSmall     bool      // Terminal is below the minimum size
+	intro        bool      // Whether the intro screen is showing
+	introSpoken  bool      // The session was announced aloud (SpeakIntro)
 	
 	// Application state
 	words        []string  // Queue of words to practice
+	wordList     []string  // Original word list, reshuffled for each loop
 	originalCount int      // Original word count for progress
+	charLimit    int       // Maximum number of characters that can be typed
+	loopsCompleted int     // Number of full passes through the word list
 	currentWord  string
+	entries      map[string]WordEntry // Word metadata (article, ...) by word
 	wordIndex    int       // Current word index in practice
 	correctCount int
+	firstTryCorrect int    // Words spelled correctly on their first attempt
+	score        int       // Points collected so far (see Scoring)
 	correctWords []string
+	totalAttempts int      // Number of submitted answers
+	submissions  int       // Submitted answers including the warm-up; ticks carry it to detect stale ones
+	currentStreak int      // Consecutive correct answers
+	bestStreak   int       // Longest streak in this session
+	finished     bool      // Whether the summary screen is showing
+	mastered     bool      // The session ended early at the target accuracy
+	stoppedEarly bool      // Whether the learner quit before finishing the list
+	aborted      bool      // Quit right away with Ctrl+C or Esc (see abort)
+	attempts     map[string]int // Number of answers submitted per word
+	
+	// Warm-up: a few words before the graded session that don't count
+	warmingUp    bool
+	warmupCount  int       // Number of warm-up words (0 = no warm-up)
+	gradedWords  []string  // The graded queue, waiting for the warm-up to end
+	
+	// Recap: the words missed during the session are practiced once more
+	// at the end (see Config.RecapMissed)
+	recapping    bool
+	missedDuringSession map[string]bool
+	recapCount   int       // Number of different words in the recap
+	recapAttempts int      // Answers given during the recap
+	recapCorrect int       // Correct answers during the recap
+	
+	// Mastery loop: rounds of the words that were never spelled correctly
+	// (see Config.MasteryLoop)
+	masteredWords map[string]bool // Words spelled correctly at least once
+	masteryRounds int      // Mastery rounds started after the last loop
+	misses       map[string]int // Number of wrong answers per word
+	retries      int       // Immediate retries of the current word in a row
 	language     string
 	localizer    *i18n.Localizer
+	config       *Config   // Loaded configuration (title, author, options)
+	styles       styleSet  // Cursor and colors from the config theme
+	speaker      Speaker   // Text-to-speech engine used to speak words
+	ttsFailures  int       // Speech commands that failed in a row
+	wordRate     int       // Speech rate of the current word (see RateJitter)
+	logger       *sessionLogger // Logs every answer with --log (nil = off)
 	
 	// Dialog state
 	dialogState  dialogState
 	dialogType   dialogType
 	dialogDiff   string
+	revealWord   bool      // Show the correct spelling prominently (auto-hint)
+	siblingWord  bool      // The wrong answer is another word of the list
+	capitalizationOnly bool // Only upper and lower case of the answer are wrong
+	manualOverride bool    // The teacher accepted the wrong answer (Ctrl+G)
+	quietCheck   bool      // Flash a check mark instead of the dialog (QuietCorrect)
+	flash        feedbackFlash // Current color of the title bar flash
+	lastInput    string    // The submitted answer, kept while the dialog shows
+	
+	// Giving up after MaxAttempts: the word is spelled out letter by letter
+	givenUp      bool
+	spelling     bool      // Letters are still being spoken
+	spellIndex   int       // Letter of the target being spoken (in runes)
+	
+	// Preview: the list is read aloud once before the practice starts
+	previewing   bool
+	previewIndex int
+	
+	// Memory mode: the word is shown briefly before the input appears
+	flashing     bool
+	
+	// Unscramble mode: the letters of the word in random order
+	scrambled    string
+	
+	// Choose mode: the spellings to pick from and the selected one
+	choices      []string
+	choiceIndex  int
+	
+	// Time limit: how long is left to type the current word (TimeLimit)
+	timeLeft     time.Duration
+	timeWarned   bool      // The learner was told to hurry (TimeWarning)
+	countdowns   int       // Countdowns started, to ignore ticks of old ones
+	
+	// Short pause between two words (see Config.InterWordPause)
+	pausing bool
 	
 	// Input state
 	inputText    string
 	showInput    bool
 	inputError   string
+	notice       string    // Short note shown below the input until the next key
+	confirming   bool      // Waiting for a second Enter (see Config.ConfirmSubmit)
+	showPhonetics bool     // Show the IPA transcription (toggled with Ctrl+P)
+	
+	// Every answer of the session for the transcript pane (ShowTranscript)
+	transcript   []transcriptEntry
 }
 
+// dialogContentWidth is the room for text inside the dialog box:
+// its width of 60 minus the horizontal padding on both sides
+const dialogContentWidth = 60 - 2*2
+
 // Styles for the TUI
 var (
 	titleBarStyle = lipgloss.NewStyle().
@@ -85,27 +209,137 @@ var (
 	incorrectDialogStyle = lipgloss.NewStyle().
 			BorderForeground(lipgloss.Color("9")).  // Red
 			Foreground(lipgloss.Color("9"))
+	
+	milestoneDialogStyle = lipgloss.NewStyle().
+			BorderForeground(lipgloss.Color("13")).  // Magenta
+			Foreground(lipgloss.Color("13"))
+	
+	capitalizationDialogStyle = lipgloss.NewStyle().
+			BorderForeground(lipgloss.Color("11")).  // Yellow
+			Foreground(lipgloss.Color("11"))
+	
+	revealStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("10")).  // Green
+			Bold(true).
+			Underline(true).
+			Padding(0, 2)
+	
+	streakStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("11")).  // Yellow
+			Bold(true)
 )
 
+// maskChar replaces typed characters when input masking is enabled
+const maskChar = '•'
+
+// inputMargin is how many characters beyond the longest answer may be typed
+const inputMargin = 10
+
+// inputCharLimit returns how many characters can be typed for the words:
+// the longest accepted answer (or phrase) plus a margin
+// Lengths are counted in runes so umlauts count as one character
+func inputCharLimit(config *Config, words []string) int {
+	entries := config.entries()
+	longest := 0
+	for _, word := range words {
+		entry, ok := entries[word]
+		if !ok {
+			entry = WordEntry{Word: word}
+		}
+		for _, answer := range entry.acceptedAnswers(config.RequireArticle) {
+			if n := utf8.RuneCountInString(answer); n > longest {
+				longest = n
+			}
+		}
+	}
+	return longest + inputMargin
+}
+
 // initialAppModel creates a new app model
-func initialAppModel(localizer *i18n.Localizer, language string, words []string) appModel {
+func initialAppModel(localizer *i18n.Localizer, config *Config, words []string) appModel {
 	return appModel{
 		localizer:      localizer,
-		language:       language,
+		language:       config.Language,
+		config:         config,
+		entries:        config.entries(),
+		styles:         newStyleSet(config.Theme),
+		speaker:        sayEngine{},
 		words:          words,
+		wordList:       append([]string(nil), words...),
 		originalCount:  len(words),
+		charLimit:      inputCharLimit(config, words),
 		correctWords:   []string{},
+		attempts:       make(map[string]int),
+		misses:         make(map[string]int),
+		missedDuringSession: make(map[string]bool),
+		masteredWords:  make(map[string]bool),
 		wordIndex:      0,
 		showInput:      false,
 		dialogState:    dialogHidden,
+		intro:          !config.SkipIntro,
+		previewing:     config.SkipIntro && config.Preview,
 	}
 }
 
-// Init initializes the model and starts the first word
+// Init initializes the model and starts the preview or the first word
+// With the intro screen, the practice starts once Enter is pressed
 func (m appModel) Init() tea.Cmd {
+	if m.intro {
+		return nil
+	}
+	if m.previewing {
+		return m.previewNext()
+	}
 	return m.startNextWord()
 }
 
+// beginPractice starts the preview if requested, or else the first word
+func (m *appModel) beginPractice() tea.Cmd {
+	m.intro = false
+	if m.config.Preview && len(m.previewList()) > 0 {
+		m.previewing = true
+		m.previewIndex = 0
+		m.updateViewportContent()
+		return m.previewNext()
+	}
+	return m.startNextWord()
+}
+
+// previewList returns the words of this session in the order of the config
+// The practice itself may use a shuffled sample of the list
+func (m *appModel) previewList() []string {
+	inSession := make(map[string]bool, len(m.wordList))
+	for _, word := range m.wordList {
+		inSession[word] = true
+	}
+	var words []string
+	for _, word := range m.config.wordList() {
+		if inSession[word] {
+			words = append(words, word)
+			delete(inSession, word) // Preview duplicates only once
+		}
+	}
+	return words
+}
+
+// previewNext speaks the current preview word, in its own language
+func (m *appModel) previewNext() tea.Cmd {
+	index := m.previewIndex
+	word := m.previewList()[index]
+	entry := m.entries[word]
+	spoken := entry.spokenText()
+	language := entry.languageOr(m.language)
+	return func() tea.Msg {
+		previewWords([]string{spoken}, language, m.speaker)
+		return previewSpokenMsg{index: index}
+	}
+}
+
+// previewSpokenMsg is sent when a preview word has been spoken
+type previewSpokenMsg struct {
+	index int
+}
+
 // Update handles messages and updates the model
 func (m appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
@@ -114,58 +348,263 @@ func (m appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		m.tooSmall = msg.Width < minTerminalWidth || msg.Height < minTerminalHeight
 		
 		headerHeight := 3 // Title bar with borders
 		if !m.ready {
-			m.viewport = viewport.New(msg.Width, msg.Height-headerHeight)
+			m.viewport = viewport.New(m.mainWidth(), msg.Height-headerHeight)
 			m.viewport.YPosition = headerHeight
 			m.ready = true
 			m.updateViewportContent()
 		} else {
-			m.viewport.Width = msg.Width
+			m.viewport.Width = m.mainWidth()
 			m.viewport.Height = msg.Height - headerHeight
 		}
 		return m, nil
 		
 	case tuiRepeatAudioMsg:
-		// Audio repetition completed - no action needed
+		// Audio repetition completed - only its outcome is noted
+		m.noteSpeechResult(msg.err)
+		return m, nil
+		
+	case soundPlayedMsg:
+		// Sound effect finished - no action needed
+		return m, nil
+		
+	case autoAdvanceMsg:
+		// Only close the dialog the timer was started for
+		if m.dialogState == dialogShowing && !m.finished && msg.attempt == m.submissions {
+			return m, m.handleDialogClose()
+		}
 		return m, nil
 		
+	case interWordPauseMsg:
+		// Move on unless the learner quit or restarted in the meantime
+		if m.pausing && msg.attempt == m.submissions {
+			m.pausing = false
+			return m, m.startNextWord()
+		}
+		return m, nil
+		
+	case feedbackFlashDoneMsg:
+		// A newer answer keeps its own flash
+		if msg.attempt == m.submissions {
+			m.flash = flashNone
+		}
+		return m, nil
+		
+	case quietCheckDoneMsg:
+		// A newer correct answer keeps its own check mark
+		if msg.attempt == m.submissions {
+			m.quietCheck = false
+		}
+		return m, nil
+		
+	case spellLetterMsg:
+		// Highlight and speak the next letter, unless the dialog was closed
+		if !m.spelling || msg.attempt != m.submissions || msg.index != m.spellIndex {
+			return m, nil
+		}
+		m.spellIndex++
+		return m, m.spellNextLetter()
+		
+	case flashDoneMsg:
+		// Hide the memorized word and ask for it
+		if m.flashing && msg.attempt == m.submissions {
+			m.flashing = false
+			m.showInput = true
+			m.updateViewportContent()
+			return m, m.startCountdown()
+		}
+		return m, nil
+		
+	case countdownMsg:
+		// The countdown stops once the word is answered
+		if msg.countdown != m.countdowns || !m.showInput || m.finished {
+			return m, nil
+		}
+		m.timeLeft -= countdownInterval
+		if m.timeLeft <= 0 {
+			// Time is up: check what was typed or chosen so far
+			return m.validateInput(m.pendingAnswer())
+		}
+		return m, tea.Batch(m.timeWarning(), m.countdownTick())
+		
+	case previewSpokenMsg:
+		// Ignore words that finish after the preview was skipped
+		if !m.previewing || msg.index != m.previewIndex {
+			return m, nil
+		}
+		m.previewIndex++
+		if m.previewIndex >= len(m.previewList()) {
+			m.previewing = false
+			return m, m.startNextWord()
+		}
+		m.updateViewportContent()
+		return m, m.previewNext()
+		
 	case speakWordMsg:
 		// Word spoken, show input prompt
+		m.noteSpeechResult(msg.err)
 		m.showInput = true
 		m.updateViewportContent()
-		return m, nil
+		return m, m.startCountdown()
 		
 	case tea.KeyMsg:
+		// Any key (including a second q) closes the summary screen,
+		// except r which practices the whole list again
+		if m.finished {
+			if msg.String() == "r" || msg.String() == "ctrl+r" {
+				return m, m.resetSession()
+			}
+			return m, tea.Quit
+		}
+		
+		// ctrl+r restarts from scratch; plain r is a letter while typing
+		if msg.String() == "ctrl+r" && !m.intro {
+			return m, m.resetSession()
+		}
+		
+		// The intro screen waits for Enter before the first word is spoken
+		if m.intro {
+			switch msg.String() {
+			case "enter":
+				return m, m.beginPractice()
+			case "q", "ctrl+c", "esc":
+				// Nothing practiced yet, so there is no summary to show
+				return m, m.abort()
+			}
+			return m, nil
+		}
+		
+		// Any key skips the rest of the preview
+		if m.previewing {
+			if isHardQuitKey(msg) {
+				return m, m.abort()
+			}
+			m.previewing = false
+			return m, m.startNextWord()
+		}
+		
 		// Handle dialog interactions
 		if m.dialogState == dialogShowing {
 			switch msg.String() {
 			case "enter", " ":
 				// Close dialog and continue to next word
 				return m, m.handleDialogClose()
-			case "q", "ctrl+c":
-				return m, tea.Quit
+			case "c":
+				// Hear the correct word to compare it with the typed one
+				if m.dialogType == dialogIncorrect {
+					return m, m.speak(m.currentEntry().spokenText())
+				}
+			case "t":
+				// Hear what was typed, to compare it phonetically
+				if m.dialogType == dialogIncorrect {
+					return m, m.speak(m.lastInput)
+				}
+			case "ctrl+g":
+				// Hidden teacher key: accept the wrong answer after all
+				if m.dialogType == dialogIncorrect && m.config.AllowTeacherOverride {
+					m.overrideAnswer()
+					return m, m.handleDialogClose()
+				}
+			case "q", "ctrl+q":
+				// Show partial results instead of quitting right away
+				return m, m.stopEarly()
+			case "ctrl+c", "esc":
+				return m, m.abort()
 			}
 			return m, nil
 		}
 		
 		// Handle input when showing input prompt
 		if m.showInput {
+			if m.notice != "" {
+				m.notice = ""
+				m.updateViewportContent()
+			}
+			
+			// Choose mode: the arrow keys select an option and Enter
+			// submits it; the other keys work as usual
+			if m.config.Mode == modeChoose {
+				switch msg.String() {
+				case "up":
+					if m.choiceIndex > 0 {
+						m.choiceIndex--
+						m.updateViewportContent()
+					}
+					return m, nil
+				case "down":
+					if m.choiceIndex < len(m.choices)-1 {
+						m.choiceIndex++
+						m.updateViewportContent()
+					}
+					return m, nil
+				case "enter":
+					return m.validateInput(m.choices[m.choiceIndex])
+				}
+				if len(msg.Runes) > 0 {
+					return m, nil // Nothing to type
+				}
+			}
+			
+			// Confirmation: Enter submits, Esc goes back to editing and
+			// any other key edits the answer right away
+			if m.confirming {
+				m.confirming = false
+				m.updateViewportContent()
+				switch msg.String() {
+				case "enter":
+					return m.validateInput(strings.TrimSpace(m.inputText))
+				case "esc":
+					return m, nil
+				}
+			}
+			
 			switch msg.String() {
 			case "enter":
 				input := strings.TrimSpace(m.inputText)
 				if input == "" {
-					validationError, _ := m.localizer.Localize(&i18n.LocalizeConfig{
-						MessageID: "ValidationError",
-					})
+					validationError := tr(m.localizer, "ValidationError")
 					m.inputError = validationError
 					m.updateViewportContent()
 					return m, nil
 				}
+				if m.config.ConfirmSubmit {
+					m.confirming = true
+					m.updateViewportContent()
+					return m, nil
+				}
 				return m.validateInput(input)
 			case "tab":
-				return m, m.repeatAudio()
+				if m.config.Mode == modeMemory {
+					return m, nil // Nothing to replay in memory mode
+				}
+				return m, m.repeatAudio(defaultRate)
+			case "shift+tab":
+				if m.config.Mode == modeMemory {
+					return m, nil
+				}
+				// Replay noticeably slower for hard words
+				return m, m.repeatAudio(slowRate)
+			case "ctrl+d":
+				return m, m.speakDefinition()
+			case "ctrl+y":
+				return m, m.speakRhyme()
+			case "ctrl+f":
+				// Bookmark the word for a later --favorites session
+				// The note doesn't name the word, which would give it away
+				m.notice = tr(m.localizer, "FavoriteAdded")
+				if err := addFavorite(m.currentWord); err != nil {
+					m.notice = tr(m.localizer, "FavoriteFailed", map[string]interface{}{"Error": err})
+				}
+				m.updateViewportContent()
+				return m, nil
+			case "ctrl+p":
+				// Toggle the transcription; it stays on for the next words
+				m.showPhonetics = !m.showPhonetics
+				m.updateViewportContent()
+				return m, nil
 			case "backspace":
 				if len(m.inputText) > 0 {
 					m.inputText = m.inputText[:len(m.inputText)-1]
@@ -173,11 +612,19 @@ func (m appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.updateViewportContent()
 				}
 				return m, nil
-			case "q", "ctrl+c":
-				return m, tea.Quit
+			case "ctrl+q":
+				// "q" is a letter here, so Ctrl+Q shows the summary
+				return m, m.stopEarly()
+			case "ctrl+c", "esc":
+				return m, m.abort()
 			default:
+				// Keys beyond the limit are ignored; a paste is cut off
 				if len(msg.Runes) > 0 {
-					m.inputText += string(msg.Runes)
+					runes := append([]rune(m.inputText), msg.Runes...)
+					if len(runes) > m.charLimit {
+						runes = runes[:m.charLimit]
+					}
+					m.inputText = string(runes)
 					m.inputError = ""
 					m.updateViewportContent()
 				}
@@ -185,9 +632,12 @@ func (m appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 		
-		// Global quit handler
-		if msg.String() == "q" || msg.String() == "ctrl+c" {
-			return m, tea.Quit
+		// Global quit handlers: q shows the summary, ctrl+c/esc quit right away
+		if msg.String() == "q" || msg.String() == "ctrl+q" {
+			return m, m.stopEarly()
+		}
+		if isHardQuitKey(msg) {
+			return m, m.abort()
 		}
 	}
 	
@@ -196,17 +646,70 @@ func (m appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// isHardQuitKey reports whether a key quits immediately, without the summary
+func isHardQuitKey(msg tea.KeyMsg) bool {
+	return msg.String() == "ctrl+c" || msg.String() == "esc"
+}
+
+// abort quits immediately; the session is neither saved nor recorded in
+// the history
+func (m *appModel) abort() tea.Cmd {
+	m.aborted = true
+	return tea.Quit
+}
+
 // View renders the TUI
 func (m appModel) View() string {
 	if !m.ready {
 		return "Initializing..."
 	}
 	
+	// Replace the whole view until the window is large enough again
+	if m.tooSmall {
+		resizeMsg := tr(m.localizer, "TerminalTooSmall", map[string]interface{}{
+			"Width":  minTerminalWidth,
+			"Height": minTerminalHeight,
+		})
+		return lipgloss.Place(
+			m.width, m.height,
+			lipgloss.Center, lipgloss.Center,
+			lipgloss.NewStyle().Width(m.width).Align(lipgloss.Center).Render(resizeMsg),
+		)
+	}
+	
 	var s strings.Builder
 	titleBar := m.renderTitleBar()
 	s.WriteString(titleBar)
 	
-	if m.dialogState == dialogShowing {
+	if m.intro {
+		// Show intro centered below title bar
+		titleBarHeight := strings.Count(titleBar, "\n") + 1
+		remainingHeight := m.height - titleBarHeight
+		if remainingHeight < 0 {
+			remainingHeight = m.height
+		}
+		
+		centeredIntro := lipgloss.Place(
+			m.width, remainingHeight,
+			lipgloss.Center, lipgloss.Center,
+			m.renderIntro(),
+		)
+		s.WriteString(centeredIntro)
+	} else if m.finished {
+		// Show summary centered below title bar
+		titleBarHeight := strings.Count(titleBar, "\n") + 1
+		remainingHeight := m.height - titleBarHeight
+		if remainingHeight < 0 {
+			remainingHeight = m.height
+		}
+		
+		centeredSummary := lipgloss.Place(
+			m.width, remainingHeight,
+			lipgloss.Center, lipgloss.Center,
+			m.renderSummary(),
+		)
+		s.WriteString(centeredSummary)
+	} else if m.dialogState == dialogShowing {
 		// Show dialog centered below title bar
 		titleBarHeight := strings.Count(titleBar, "\n") + 1
 		remainingHeight := m.height - titleBarHeight
@@ -216,14 +719,14 @@ func (m appModel) View() string {
 		
 		dialog := m.renderDialog()
 		centeredDialog := lipgloss.Place(
-			m.width, remainingHeight,
+			m.mainWidth(), remainingHeight,
 			lipgloss.Center, lipgloss.Center,
 			dialog,
 		)
-		s.WriteString(centeredDialog)
+		s.WriteString(m.withTranscript(centeredDialog, remainingHeight))
 	} else {
 		// Show viewport content
-		s.WriteString(m.viewport.View())
+		s.WriteString(m.withTranscript(m.viewport.View(), m.viewport.Height))
 	}
 	
 	return s.String()
@@ -231,28 +734,121 @@ func (m appModel) View() string {
 
 // renderTitleBar renders the title bar with progress information
 func (m appModel) renderTitleBar() string {
-	wordsList := strings.Join(m.correctWords, ", ")
+	completed := m.correctWords
+	if m.config.SortCompletedWords {
+		completed = collateWords(completed, m.language)
+	}
+	wordsList := strings.Join(completed, ", ")
 	coloredWordsList := ""
 	if wordsList != "" {
 		coloredWordsList = turquoiseStyle.Render(wordsList)
 	}
 	
-	progressMsg, _ := m.localizer.Localize(&i18n.LocalizeConfig{
-		MessageID: "ProgressMessage",
-		TemplateData: map[string]interface{}{
-			"Current":   m.wordIndex + 1,
-			"Completed": m.correctCount,
-			"Total":     m.originalCount,
-			"Words":     coloredWordsList,
-		},
+	// Only entries with an explicit difficulty show one; an empty
+	// Difficulty leaves it out of the message
+	difficulty := ""
+	if id, ok := difficultyMessageIDs[m.currentEntry().Difficulty]; ok && m.config.ShowDifficulty {
+		difficulty = tr(m.localizer, id)
+	}
+	
+	progressMsg := tr(m.localizer, "ProgressMessage", map[string]interface{}{
+		"Current":    m.wordIndex + 1,
+		"Difficulty": difficulty,
+		"Completed":  m.correctCount,
+		"Total":      m.originalCount * (m.loopsCompleted + 1),
+		"Remaining":  m.remaining(),
+		"Words":      coloredWordsList,
 	})
 	
+	// The warm-up has its own progress, since it isn't scored
+	if m.warmingUp {
+		progressMsg = tr(m.localizer, "WarmupMessage", map[string]interface{}{
+			"Current": m.wordIndex + 1,
+			"Total":   m.warmupCount,
+		})
+	}
+	if m.masteryRounds > 0 && !m.recapping {
+		progressMsg = tr(m.localizer, "MasteryRoundMessage", map[string]interface{}{
+			"Round":     m.masteryRounds,
+			"Remaining": m.remaining(),
+		})
+	}
+	if m.recapping {
+		progressMsg = tr(m.localizer, "RecapMessage", map[string]interface{}{"Remaining": m.remaining()})
+	}
+	
+	// Show the current round when the list is repeated
+	if m.config.Loops != 1 && !m.finished {
+		roundMsg := tr(m.localizer, "RoundMessage", map[string]interface{}{"Round": m.loopsCompleted + 1})
+		progressMsg = roundMsg + " · " + progressMsg
+	}
+	
+	scoreMsg := tr(m.localizer, "ScoreMessage", map[string]interface{}{"Score": m.score})
+	progressMsg += " · " + scoreMsg
+	
+	if m.currentStreak > 0 {
+		streakMsg := tr(m.localizer, "StreakMessage", map[string]interface{}{"Streak": m.currentStreak})
+		progressMsg += " " + streakStyle.Render(streakMsg)
+	}
+	
+	if m.quietCheck {
+		progressMsg = successStyle.Render("✔") + " " + progressMsg
+	}
+	
+	// The time left for the current word, in red once it is running out
+	if m.config.TimeLimit > 0 && m.showInput {
+		timeMsg := tr(m.localizer, "TimeLeft", map[string]interface{}{"Seconds": int(m.timeLeft.Seconds())})
+		if m.timeWarned {
+			timeMsg = errorStyle.Render(timeMsg)
+		}
+		progressMsg += " · " + timeMsg
+	}
+	
+	// Speech keeps failing: say so, instead of leaving learners in silence
+	if m.ttsFailures >= ttsFailureLimit {
+		progressMsg += " · " + errorStyle.Render(tr(m.localizer, "AudioUnavailable"))
+	}
+	
 	// Width minus 2 for border characters (left + right)
 	contentWidth := m.width - 2
 	if contentWidth < 0 {
 		contentWidth = m.width
 	}
-	return titleBarStyle.Width(contentWidth).Render("🔊 " + progressMsg)
+	style := titleBarStyle
+	switch m.flash {
+	case flashGreen:
+		style = style.Background(lipgloss.Color("2")) // Dark green, so the white text stays readable
+	case flashRed:
+		style = style.Background(lipgloss.Color("1")) // Dark red
+	}
+	return style.Width(contentWidth).Render("🔊 " + m.sessionTitle() + " · " + progressMsg)
+}
+
+// remaining returns how many answers are still queued, including the
+// current word; requeued words count again, so this is the real workload
+func (m appModel) remaining() int {
+	if m.wordIndex >= len(m.words) {
+		return 0
+	}
+	return len(m.words) - m.wordIndex
+}
+
+// sessionTitle returns the word list title from the config,
+// falling back to the localized default title when none is set
+func (m appModel) sessionTitle() string {
+	title := m.config.Title
+	if title == "" {
+		title = tr(m.localizer, "Title")
+	}
+	if m.config.Author == "" {
+		return title
+	}
+	
+	titleByAuthor := tr(m.localizer, "TitleByAuthor", map[string]interface{}{
+		"Title":  title,
+		"Author": m.config.Author,
+	})
+	return titleByAuthor
 }
 
 // renderDialog renders the feedback dialog
@@ -261,10 +857,16 @@ func (m appModel) renderDialog() string {
 	var style lipgloss.Style
 	
 	if m.dialogType == dialogCorrect {
-		title, _ = m.localizer.Localize(&i18n.LocalizeConfig{MessageID: "Correct"})
+		title = tr(m.localizer, "Correct")
 		style = dialogBoxStyle.Copy().Inherit(correctDialogStyle)
+	} else if m.dialogType == dialogMilestone {
+		title = tr(m.localizer, "StreakMilestone", map[string]interface{}{"Streak": m.currentStreak})
+		style = dialogBoxStyle.Copy().Inherit(milestoneDialogStyle)
+	} else if m.capitalizationOnly {
+		title = tr(m.localizer, "CapitalizationOnly")
+		style = dialogBoxStyle.Copy().Inherit(capitalizationDialogStyle)
 	} else {
-		title, _ = m.localizer.Localize(&i18n.LocalizeConfig{MessageID: "IncorrectSpelling"})
+		title = tr(m.localizer, "IncorrectSpelling")
 		style = dialogBoxStyle.Copy().Inherit(incorrectDialogStyle)
 	}
 	
@@ -276,16 +878,178 @@ func (m appModel) renderDialog() string {
 		dialog.WriteString(m.dialogDiff)
 	}
 	
-	pressEnterMsg, _ := m.localizer.Localize(&i18n.LocalizeConfig{
-		MessageID: "PressEnterToContinue",
-	})
+	if m.revealWord {
+		revealMsg := tr(m.localizer, "RevealWord")
+		dialog.WriteString("\n\n" + revealMsg + "\n")
+		dialog.WriteString(revealStyle.Render(m.target()))
+		dialog.WriteString("\n")
+	}
+	
+	if m.dialogType == dialogIncorrect && m.siblingWord {
+		siblingNote := tr(m.localizer, "SiblingWord")
+		dialog.WriteString("\n\n" + labelStyle.Render(siblingNote))
+	}
+	
+	if m.givenUp {
+		giveUp := tr(m.localizer, "GiveUpSpelling")
+		dialog.WriteString("\n\n" + giveUp + "\n")
+		dialog.WriteString(m.renderSpelling())
+		dialog.WriteString("\n")
+	}
+	
+	if m.dialogType == dialogIncorrect {
+		compareHint := tr(m.localizer, "CompareHint")
+		dialog.WriteString("\n" + compareHint + "\n")
+	}
+	
+	pressEnterMsg := tr(m.localizer, "PressEnterToContinue")
 	dialog.WriteString("\n(" + pressEnterMsg + ")")
 	
 	return style.Render(dialog.String())
 }
 
+// renderIntro renders the intro screen shown before the first word
+// It gives learners a moment to prepare, like the CLI's "Press Enter" prompt
+func (m appModel) renderIntro() string {
+	subtitle := tr(m.localizer, "Subtitle")
+	instructions := tr(m.localizer, "PracticeInstructions", map[string]interface{}{"Count": m.originalCount})
+	pressEnter := tr(m.localizer, "PressEnter")
+	begin := tr(m.localizer, "PressEnterToBegin")
+	
+	lines := []string{
+		dialogTitleStyle.Render(m.sessionTitle()),
+		subtitle,
+		"",
+		instructions,
+		pressEnter,
+		"",
+		"(" + begin + ")",
+	}
+	return dialogBoxStyle.Render(strings.Join(lines, "\n"))
+}
+
+// renderSummary renders the final statistics shown when practice is complete
+func (m appModel) renderSummary() string {
+	// Accuracy is the share of attempts that were spelled correctly
+	accuracy := 0
+	if m.totalAttempts > 0 {
+		accuracy = m.correctCount * 100 / m.totalAttempts
+	}
+	
+	titleID := "PracticeComplete"
+	if m.stoppedEarly {
+		titleID = "PracticeStopped"
+	} else if m.mastered {
+		titleID = "MasteryAchieved"
+	}
+	title := tr(m.localizer, titleID)
+	lines := []string{
+		dialogTitleStyle.Render(title),
+		"",
+	}
+	
+	stats := []struct {
+		id   string
+		data map[string]interface{}
+	}{
+		{"WordsPracticed", map[string]interface{}{"Count": m.originalCount}},
+		{"TotalAttempts", map[string]interface{}{"Count": m.totalAttempts}},
+		{"Accuracy", map[string]interface{}{"Percent": accuracy}},
+		{"FirstTryStats", map[string]interface{}{
+			"FirstTry":   m.firstTryCorrect,
+			"Eventually": m.correctCount - m.firstTryCorrect,
+		}},
+		{"BestStreak", map[string]interface{}{"Count": m.bestStreak}},
+		{"ScoreMessage", map[string]interface{}{"Score": m.score}},
+	}
+	if m.config.Loops != 1 {
+		stats = append(stats, struct {
+			id   string
+			data map[string]interface{}
+		}{"LoopsCompleted", map[string]interface{}{"Count": m.loopsCompleted}})
+	}
+	for _, stat := range stats {
+		line := tr(m.localizer, stat.id, stat.data)
+		lines = append(lines, line)
+	}
+	// Rounds needed: the loops plus the mastery rounds after them
+	if m.config.MasteryLoop {
+		lines = append(lines, tr(m.localizer, "MasteryRounds", map[string]interface{}{
+			"Count": m.loopsCompleted + m.masteryRounds,
+		}))
+	}
+	// The recap is reported on its own, since it only has the hard words
+	if m.recapAttempts > 0 {
+		lines = append(lines, tr(m.localizer, "RecapAccuracy", map[string]interface{}{
+			"Percent": m.recapCorrect * 100 / m.recapAttempts,
+			"Count":   m.recapCount,
+		}))
+	}
+	if m.config.GroupSummaryByInitial {
+		lines = append(lines, m.missedByInitial()...)
+	}
+	if m.warmupCount > 0 {
+		warmupNote := tr(m.localizer, "WarmupNote", map[string]interface{}{"Count": m.warmupCount})
+		lines = append(lines, "", labelStyle.Render(warmupNote))
+	}
+	
+	pressEnterMsg := tr(m.localizer, "PressAnyKeyToExit")
+	restartHint := tr(m.localizer, "RestartHint")
+	lines = append(lines, "", "("+pressEnterMsg+")", "("+restartHint+")")
+	
+	return dialogBoxStyle.Render(strings.Join(lines, "\n"))
+}
+
+// missedByInitial lists the missed words of the summary grouped by their
+// first letter, one line per letter ("H: Haus, Hund"), under a heading;
+// nothing when no word was missed
+func (m appModel) missedByInitial() []string {
+	missed := make([]string, 0, len(m.missedDuringSession))
+	for word := range m.missedDuringSession {
+		missed = append(missed, word)
+	}
+	if len(missed) == 0 {
+		return nil
+	}
+	// Sorting first keeps the words of each bucket in alphabetical order
+	groups := groupByInitial(collateWords(missed, m.language))
+	initials := make([]string, 0, len(groups))
+	for initial := range groups {
+		initials = append(initials, string(initial))
+	}
+	
+	lines := []string{"", labelStyle.Render(tr(m.localizer, "MissedByInitial"))}
+	for _, initial := range collateWords(initials, m.language) {
+		words := groups[[]rune(initial)[0]]
+		lines = append(lines, errorStyle.Render(initial)+": "+strings.Join(words, ", "))
+	}
+	return lines
+}
+
 // updateViewportContent updates the viewport content
 func (m *appModel) updateViewportContent() {
+	if m.previewing {
+		words := m.previewList()
+		previewMsg := tr(m.localizer, "PreviewPrompt", map[string]interface{}{
+			"Current": m.previewIndex + 1,
+			"Total":   len(words),
+		})
+		skipHint := tr(m.localizer, "PreviewSkipHint")
+		m.viewport.SetContent(previewMsg + "\n\n" + revealStyle.Render(words[m.previewIndex]) + "\n\n" + skipHint)
+		return
+	}
+	
+	if m.pausing {
+		m.viewport.SetContent(labelStyle.Render(tr(m.localizer, "NextWord")))
+		return
+	}
+	
+	if m.flashing {
+		memorize := tr(m.localizer, "MemorizePrompt", map[string]interface{}{"Number": m.wordIndex + 1})
+		m.viewport.SetContent(memorize + "\n\n" + revealStyle.Render(m.target()))
+		return
+	}
+	
 	if !m.showInput {
 		m.viewport.SetContent("Waiting for next word...")
 		return
@@ -293,32 +1057,144 @@ func (m *appModel) updateViewportContent() {
 	
 	var content strings.Builder
 	
-	title, _ := m.localizer.Localize(&i18n.LocalizeConfig{
-		MessageID: "WordPrompt",
-		TemplateData: map[string]interface{}{"Number": m.wordIndex + 1},
-	})
-	placeholder, _ := m.localizer.Localize(&i18n.LocalizeConfig{MessageID: "Placeholder"})
-	tabHint, _ := m.localizer.Localize(&i18n.LocalizeConfig{MessageID: "TabHint"})
+	promptID := "WordPrompt"
+	if m.config.Mode == modeMemory {
+		promptID = "MemoryPrompt"
+	} else if m.config.Mode == modeUnscramble {
+		promptID = "UnscramblePrompt"
+	} else if m.config.Mode == modeCopy {
+		promptID = "CopyPrompt"
+	} else if m.config.Mode == modeChoose {
+		promptID = "ChoosePrompt"
+	}
+	title := tr(m.localizer, promptID, map[string]interface{}{"Number": m.wordIndex + 1})
+	placeholder := tr(m.localizer, "Placeholder")
+	tabHint := tr(m.localizer, "TabHint")
+	slowHint := tr(m.localizer, "SlowReplayHint")
 	
 	content.WriteString(title)
 	content.WriteString("\n\n")
 	
+	if m.config.Mode == modeUnscramble {
+		content.WriteString(revealStyle.Render(m.scrambled))
+		content.WriteString("\n\n")
+	}
+	// Copy practice: the word stays visible while it is typed
+	if m.config.Mode == modeCopy {
+		content.WriteString(revealStyle.Render(m.target()))
+		content.WriteString("\n\n")
+	}
+	
+	// Grammar practice: tell the learner which form to type
+	if entry := m.currentEntry(); entry.Expected != "" {
+		instruction := entry.Instruction
+		if instruction == "" {
+			instruction = tr(m.localizer, "TransformHint")
+		}
+		content.WriteString(labelStyle.Render(instruction))
+		content.WriteString("\n\n")
+	}
+	
+	// Length hint: one blank per letter, e.g. "____  (4 letters)"
+	if m.config.ShowLength {
+		content.WriteString(lengthHint(m.target(), m.localizer))
+		content.WriteString("\n\n")
+	}
+	
+	var input string
 	if m.inputText == "" {
-		content.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("8")).Render(placeholder))
+		input = m.styles.placeholder.Render(placeholder)
+	} else if m.config.MaskInput {
+		// Blind type-along: show one dot per typed character
+		input = strings.Repeat(string(maskChar), utf8.RuneCountInString(m.inputText))
 	} else {
-		content.WriteString(m.inputText)
+		input = m.inputText
+	}
+	if m.config.Mode == modeChoose {
+		// Nothing is typed: the options take the place of the input
+		content.WriteString(m.renderChoices() + "\n\n")
+	} else if m.config.isRTLFor(m.wordLanguage()) {
+		// Right-to-left text grows to the left, so the cursor goes first
+		content.WriteString(m.styles.cursor + input + "\n\n")
+	} else {
+		content.WriteString(input + m.styles.cursor + "\n\n")
 	}
-	content.WriteString("█\n\n")
 	
+	if m.showPhonetics {
+		content.WriteString(labelStyle.Render(m.phoneticsHint()))
+		content.WriteString("\n")
+	}
 	if m.inputError != "" {
 		content.WriteString(errorStyle.Render("❌ " + m.inputError))
 		content.WriteString("\n")
 	}
+	if m.confirming {
+		// The answer as shown above, so masked input stays hidden
+		confirm := tr(m.localizer, "ConfirmSubmit", map[string]interface{}{"Input": input})
+		content.WriteString(labelStyle.Render(confirm))
+		content.WriteString("\n")
+	}
+	if m.notice != "" {
+		content.WriteString(labelStyle.Render(m.notice))
+		content.WriteString("\n")
+	}
+	
+	if m.config.Mode != modeMemory {
+		content.WriteString(tabHint)
+		content.WriteString("\n")
+		content.WriteString(slowHint)
+	}
+	if len(m.config.Definitions) > 0 {
+		definitionHint := tr(m.localizer, "DefinitionHint")
+		content.WriteString("\n")
+		content.WriteString(definitionHint)
+	}
+	if len(m.config.Phonetics) > 0 {
+		phoneticsHint := tr(m.localizer, "PhoneticsHint")
+		content.WriteString("\n")
+		content.WriteString(phoneticsHint)
+	}
+	if len(m.config.Rhymes) > 0 {
+		rhymeHint := tr(m.localizer, "RhymeHint")
+		content.WriteString("\n")
+		content.WriteString(rhymeHint)
+	}
+	// How to type the umlauts and accents of the word on this system
+	if m.config.ShowTypingHints {
+		if hints := typingHintsFor(m.target(), runtime.GOOS); len(hints) > 0 {
+			typingHints := tr(m.localizer, "TypingHints", map[string]interface{}{"Hints": strings.Join(hints, " · ")})
+			content.WriteString("\n")
+			content.WriteString(typingHints)
+		}
+	}
 	
-	content.WriteString(tabHint)
+	if m.config.isRTLFor(m.wordLanguage()) {
+		// Align the whole prompt to the right edge for right-to-left languages
+		m.viewport.SetContent(lipgloss.NewStyle().
+			Width(m.viewport.Width).
+			Align(lipgloss.Right).
+			Render(content.String()))
+		return
+	}
 	m.viewport.SetContent(content.String())
 }
 
+// lengthHint renders a blank for every letter of target, followed by the
+// letter count, or the counts of each word for phrases ("___ ____  (3, 4 letters)")
+// Letters are counted as runes, so "ü" or "ß" count as one letter each
+func lengthHint(target string, localizer *i18n.Localizer) string {
+	words := strings.Fields(target)
+	blanks := make([]string, len(words))
+	counts := make([]string, len(words))
+	for i, word := range words {
+		n := utf8.RuneCountInString(word)
+		blanks[i] = strings.Repeat("_", n)
+		counts[i] = strconv.Itoa(n)
+	}
+	letters := tr(localizer, "LengthHint", map[string]interface{}{"Lengths": strings.Join(counts, ", ")})
+	return strings.Join(blanks, " ") + "  " + labelStyle.Render(letters)
+}
+
 // validateInput validates the user input and shows feedback
 func (m *appModel) validateInput(input string) (tea.Model, tea.Cmd) {
 	if m.currentWord == "" {
@@ -330,77 +1206,869 @@ func (m *appModel) validateInput(input string) (tea.Model, tea.Cmd) {
 		}
 	}
 	
-	if input == m.currentWord {
-		m.correctCount++
-		m.correctWords = append(m.correctWords, m.currentWord)
+	// The warm-up is unscored: it changes none of the counters, the score
+	// or the streaks, which are also what a saved session keeps
+	scored := !m.warmingUp
+	m.submissions++
+	if scored {
+		m.totalAttempts++
+		m.attempts[m.currentWord]++
+	}
+	m.revealWord = false
+	m.lastInput = input
+	
+	// The diff compares against the accepted answer closest to the input
+	target, correct := matchAnswer(input, m.currentEntry().acceptedAnswers(m.config.RequireArticle), m.wordLanguage(), m.config)
+	if scored {
+		m.logger.LogAttempt(m.currentWord, input, correct, m.attempts[m.currentWord])
+		m.transcript = append(m.transcript, transcriptEntry{word: target, input: input, correct: correct})
+	}
+	if m.recapping {
+		m.recapAttempts++
+		if correct {
+			m.recapCorrect++
+		}
+	}
+	if correct {
+		if scored {
+			m.masteredWords[m.currentWord] = true
+			m.correctCount++
+			if m.attempts[m.currentWord] == 1 {
+				m.firstTryCorrect++
+			}
+			outcome := correctOutcome(m.attempts[m.currentWord], m.wasRevealed())
+			m.score += scoreFor(outcome, m.config.Scoring)
+			m.correctWords = append(m.correctWords, m.currentWord)
+			m.currentStreak++
+			if m.currentStreak > m.bestStreak {
+				m.bestStreak = m.currentStreak
+			}
+		}
 		m.dialogType = dialogCorrect
+		if isStreakMilestone(m.currentStreak) {
+			m.dialogType = dialogMilestone
+		}
 		m.dialogDiff = ""
+		if diffInput, diffTarget := diffTexts(input, target, m.wordLanguage(), m.config); diffInput != diffTarget {
+			// Accepted, but not exact (e.g., missing umlaut): show the
+			// proper spelling so the learner still notices the difference
+			m.dialogDiff = m.formatDiff(input, target)
+		}
 	} else {
 		m.dialogType = dialogIncorrect
-		m.dialogDiff = formatWordDiff(input, m.currentWord, m.localizer)
+		if scored {
+			m.currentStreak = 0
+			m.score += scoreFor(outcomeWrong, m.config.Scoring)
+			m.misses[m.currentWord]++
+			if !m.recapping {
+				m.missedDuringSession[m.currentWord] = true
+			}
+		}
+		// After enough misses of the same word, reveal it as a hint
+		if m.wasRevealed() {
+			m.revealWord = true
+		}
+		m.siblingWord = m.config.SiblingHint && m.config.isSiblingWord(input, m.currentWord)
+		// Right letters, wrong case: encourage instead of the generic message
+		m.capitalizationOnly = m.config.CapitalizationHint && isCapitalizationOnly(input, target, m.wordLanguage(), m.config)
+		// After too many, give up and spell it out
+		if m.config.MaxAttempts > 0 && m.misses[m.currentWord] >= m.config.MaxAttempts {
+			m.givenUp = true
+			m.spelling = true
+			m.spellIndex = 0
+		}
+		if m.config.ShowDiff {
+			m.dialogDiff = m.formatDiff(input, target)
+		} else {
+			// Listening mode: reveal the correct spelling without the diff
+			correctLabel := tr(m.localizer, "CorrectLabel")
+			m.dialogDiff = labelStyle.Render(correctLabel) + " " + target
+		}
 	}
 	
-	m.dialogState = dialogShowing
 	m.inputText = ""
 	m.inputError = ""
 	m.showInput = false
+	flash := m.flashFeedback(correct)
 	
-	return m, nil
+	// Quick drilling: exact correct answers skip the dialog and only flash
+	// a check mark in the title bar; accepted-but-different answers still
+	// show the dialog, so the proper spelling isn't missed
+	if m.config.QuietCorrect && m.dialogType != dialogIncorrect && m.dialogDiff == "" {
+		m.quietCheck = true
+		attempt := m.submissions
+		clearCheck := tea.Tick(quietCheckDuration, func(time.Time) tea.Msg {
+			return quietCheckDoneMsg{attempt: attempt}
+		})
+		return m, tea.Batch(m.playSound(soundCorrect), flash, clearCheck, m.handleDialogClose())
+	}
+	
+	m.dialogState = dialogShowing
+	if m.dialogType == dialogIncorrect {
+		if m.spelling {
+			return m, tea.Batch(m.playSound(soundIncorrect), flash, m.spellNextLetter())
+		}
+		return m, tea.Batch(m.playSound(soundIncorrect), flash)
+	}
+	return m, tea.Batch(m.playSound(soundCorrect), flash, m.scheduleAutoAdvance())
+}
+
+// flashFeedback starts the title bar flash for a checked answer and
+// returns the timer that ends it, or nil when FlashFeedback is off
+func (m *appModel) flashFeedback(correct bool) tea.Cmd {
+	if !m.config.FlashFeedback {
+		return nil
+	}
+	m.flash = flashRed
+	if correct {
+		m.flash = flashGreen
+	}
+	attempt := m.submissions
+	return tea.Tick(feedbackFlashDuration, func(time.Time) tea.Msg {
+		return feedbackFlashDoneMsg{attempt: attempt}
+	})
 }
 
-// repeatAudio repeats the audio for the current word
-func (m *appModel) repeatAudio() tea.Cmd {
+// feedbackFlashDuration is how long the title bar flashes (FlashFeedback)
+const feedbackFlashDuration = 300 * time.Millisecond
+
+// feedbackFlashDoneMsg is sent when the title bar flash should end
+type feedbackFlashDoneMsg struct {
+	attempt int // submissions when the flash started
+}
+
+// quietCheckDuration is how long the check mark of QuietCorrect is shown
+const quietCheckDuration = time.Second
+
+// quietCheckDoneMsg is sent when the check mark should disappear again
+type quietCheckDoneMsg struct {
+	attempt int // submissions when the check mark appeared
+}
+
+// overrideAnswer turns the last wrong answer into a correct one, when the
+// teacher accepts it: the miss is taken back and the word counts as done
+func (m *appModel) overrideAnswer() {
+	m.manualOverride = true
+	if m.warmingUp {
+		return // The warm-up didn't count the miss in the first place
+	}
+	m.misses[m.currentWord]--
+	if m.misses[m.currentWord] == 0 {
+		delete(m.missedDuringSession, m.currentWord)
+	}
+	if m.recapping {
+		m.recapCorrect++
+	}
+	m.masteredWords[m.currentWord] = true
+	m.correctCount++
+	if m.attempts[m.currentWord] == 1 {
+		m.firstTryCorrect++
+	}
+	m.correctWords = append(m.correctWords, m.currentWord)
+	
+	// Swap the points of the wrong answer for those of a correct one
+	m.score -= scoreFor(outcomeWrong, m.config.Scoring)
+	m.score += scoreFor(correctOutcome(m.attempts[m.currentWord], m.wasRevealed()), m.config.Scoring)
+}
+
+// spellLetterMsg is sent when a letter of a given-up word has been spoken
+type spellLetterMsg struct {
+	attempt int // submissions when the spelling started
+	index   int // The letter that was spoken
+}
+
+// spellNextLetter speaks the letter at spellIndex, or ends the spelling
+// after the last one; the dialog highlights the letter while it is spoken
+func (m *appModel) spellNextLetter() tea.Cmd {
+	letters := []rune(m.target())
+	if m.spellIndex >= len(letters) {
+		m.spelling = false
+		return nil
+	}
+	letter := letters[m.spellIndex]
+	index := m.spellIndex
+	attempt := m.submissions
+	language := m.wordLanguage()
+	speaker := m.speaker
 	return func() tea.Msg {
-		if err := speakWord(m.currentWord, m.language); err != nil {
-			// Silently fail
+		_ = speakLetter(letter, language, speaker)
+		return spellLetterMsg{attempt: attempt, index: index}
+	}
+}
+
+// renderSpelling renders the target with the letter being spoken highlighted
+func (m appModel) renderSpelling() string {
+	var spelled strings.Builder
+	for i, letter := range []rune(m.target()) {
+		if i > 0 {
+			spelled.WriteString(" ")
+		}
+		if m.spelling && i == m.spellIndex {
+			spelled.WriteString(revealStyle.Render(string(letter)))
+		} else {
+			spelled.WriteString(string(letter))
 		}
-		return tuiRepeatAudioMsg{}
+	}
+	return spelled.String()
+}
+
+// scheduleAutoAdvance returns a timer command that closes the correct
+// dialog after the configured delay, or nil when auto-advance is off
+func (m *appModel) scheduleAutoAdvance() tea.Cmd {
+	if m.config.AutoAdvance <= 0 {
+		return nil
+	}
+	// Remember which answer the timer belongs to, so a late tick can't
+	// close a dialog the learner has already moved past
+	attempt := m.submissions
+	return tea.Tick(m.config.AutoAdvance, func(time.Time) tea.Msg {
+		return autoAdvanceMsg{attempt: attempt}
+	})
+}
+
+// autoAdvanceMsg is sent when the auto-advance delay has passed
+type autoAdvanceMsg struct {
+	attempt int // submissions when the timer was started
+}
+
+// countdownInterval is how often the time limit counts down
+// It is a variable so tests don't have to wait for real seconds
+var countdownInterval = time.Second
+
+// startCountdown starts the time limit for the current word, or returns
+// nil when there is no limit
+func (m *appModel) startCountdown() tea.Cmd {
+	if m.config.TimeLimit <= 0 {
+		return nil
+	}
+	m.timeLeft = m.config.TimeLimit
+	m.timeWarned = false
+	// A new number for every countdown: a word asked again must not be
+	// counted down twice as fast by the ticks of its previous countdown
+	m.countdowns++
+	return m.countdownTick()
+}
+
+// countdownTick waits one interval of the current countdown
+func (m *appModel) countdownTick() tea.Cmd {
+	countdown := m.countdowns
+	return tea.Tick(countdownInterval, func(time.Time) tea.Msg {
+		return countdownMsg{countdown: countdown}
+	})
+}
+
+// countdownMsg is sent every countdownInterval while a word is timed
+type countdownMsg struct {
+	countdown int // The countdown the tick belongs to
+}
+
+// timeWarning returns a command that tells the learner to hurry, the
+// first time the remaining time drops to TimeWarning; nil otherwise
+func (m *appModel) timeWarning() tea.Cmd {
+	if m.timeWarned || m.config.TimeWarning <= 0 || m.timeLeft > m.config.TimeWarning {
+		return nil
+	}
+	m.timeWarned = true
+	// Spoken in the language of the app, like the other instructions
+	hurry := tr(m.localizer, "Hurry")
+	return func() tea.Msg {
+		err := m.speaker.Speak(hurry, m.language, defaultRate)
+		return tuiRepeatAudioMsg{err: err}
+	}
+}
+
+// wasRevealed reports whether the correct spelling of the current word has
+// already been revealed as a hint (see AutoRevealAfter)
+func (m *appModel) wasRevealed() bool {
+	return m.config.AutoRevealAfter > 0 && m.misses[m.currentWord] >= m.config.AutoRevealAfter
+}
+
+// currentEntry returns the word entry (with metadata) for the current word
+func (m *appModel) currentEntry() WordEntry {
+	if entry, ok := m.entries[m.currentWord]; ok {
+		return entry
+	}
+	return WordEntry{Word: m.currentWord}
+}
+
+// wordLanguage returns the language of the current word, which can
+// differ from the list language in mixed vocabulary lists
+func (m *appModel) wordLanguage() string {
+	return m.currentEntry().languageOr(m.language)
+}
+
+// target returns what the learner has to type for the current word
+func (m *appModel) target() string {
+	return m.currentEntry().target(m.config.RequireArticle)
+}
+
+// formatDiff renders the diff between the input and the expected answer
+// using the themed styles and the writing direction of the language;
+// almost correct answers are highlighted in the near-miss color
+func (m *appModel) formatDiff(input, target string) string {
+	input, target = diffTexts(input, target, m.wordLanguage(), m.config)
+	return formatWordDiff(input, target, m.localizer,
+		withStyles(m.styles), withRTL(m.config.isRTLFor(m.wordLanguage())),
+		withSeverity(severityFor(input, target)),
+		withTranspositions(m.config.MarkTranspositions),
+		withMaxWidth(dialogContentWidth))
+}
+
+// repeatAudio repeats the audio for the current word at the given rate
+// At the normal rate long phrases are chunked like the first time, at the
+// rate the word was first spoken at; the slow replay reads the whole
+// phrase slowly instead
+func (m *appModel) repeatAudio(rate int) tea.Cmd {
+	return func() tea.Msg {
+		spoken := m.currentEntry().spokenText()
+		var err error
+		if rate == defaultRate {
+			err = speakPhrase(spoken, m.wordLanguage(), m.speechRate(), m.config.ChunkPhrases, m.speaker)
+		} else {
+			err = m.speaker.Speak(spoken, m.wordLanguage(), rate)
+		}
+		return tuiRepeatAudioMsg{err: err}
+	}
+}
+
+// speechRate returns the rate of the current word, or the normal rate
+// before the first word
+func (m *appModel) speechRate() int {
+	if m.wordRate == 0 {
+		return defaultRate
+	}
+	return m.wordRate
+}
+
+// speakDefinition speaks the meaning of the current word from the config
+// Without a definition it only shows a short note instead
+func (m *appModel) speakDefinition() tea.Cmd {
+	definition, ok := m.config.Definitions[m.currentWord]
+	if !ok || definition == "" {
+		m.notice = tr(m.localizer, "NoDefinition")
+		m.updateViewportContent()
+		return nil
+	}
+	return m.speak(definition)
+}
+
+// speakRhyme speaks the rhyming word configured for the current word
+// Without one it only shows a short note instead
+func (m *appModel) speakRhyme() tea.Cmd {
+	rhyme, ok := m.config.Rhymes[m.currentWord]
+	if !ok || rhyme == "" {
+		m.notice = tr(m.localizer, "NoRhyme")
+		m.updateViewportContent()
+		return nil
+	}
+	return m.speak(rhyme)
+}
+
+// phoneticsHint returns the IPA transcription of the current word, or a
+// note that the config has none for it
+func (m *appModel) phoneticsHint() string {
+	ipa, ok := m.config.Phonetics[m.currentWord]
+	if !ok || ipa == "" {
+		return tr(m.localizer, "NoPhonetics")
+	}
+	return "🗣 /" + ipa + "/"
+}
+
+// speak returns a command that speaks the given text at the normal rate
+// It reuses tuiRepeatAudioMsg since nothing needs to happen afterwards
+func (m *appModel) speak(text string) tea.Cmd {
+	return func() tea.Msg {
+		err := m.speaker.Speak(text, m.wordLanguage(), defaultRate)
+		return tuiRepeatAudioMsg{err: err}
 	}
 }
 
 // tuiRepeatAudioMsg is sent when audio repetition completes in TUI
-type tuiRepeatAudioMsg struct{}
+type tuiRepeatAudioMsg struct {
+	err error // Why the audio couldn't be played, if it failed
+}
+
+// ttsFailureLimit is the number of failed speech commands in a row after
+// which the title bar shows that audio is unavailable
+const ttsFailureLimit = 3
+
+// noteSpeechResult counts failed speech commands in a row
+// Commands run in the background, so their errors arrive as part of the
+// message sent when they are done; a single success resets the count
+func (m *appModel) noteSpeechResult(err error) {
+	if err != nil {
+		m.ttsFailures++
+	} else {
+		m.ttsFailures = 0
+	}
+}
+
+// playSound returns a command that plays a sound effect in the background
+// It returns nil (no command) when sound effects are disabled in the config
+func (m *appModel) playSound(name string) tea.Cmd {
+	if !m.config.SoundEffects {
+		return nil
+	}
+	return func() tea.Msg {
+		// Sound effects are optional, so playback errors are ignored
+		_ = playSound(name)
+		return soundPlayedMsg{}
+	}
+}
+
+// soundPlayedMsg is sent when a sound effect has finished playing
+type soundPlayedMsg struct{}
 
 // startNextWord starts the next word in the queue
+// When the queue is exhausted, it either starts the next loop over the
+// reshuffled word list, a mastery round, the recap of missed words or
+// switches to the summary screen
 func (m *appModel) startNextWord() tea.Cmd {
+	if m.warmingUp && m.wordIndex >= len(m.words) {
+		m.endWarmup()
+	}
 	if m.wordIndex >= len(m.words) {
-		return tea.Quit
+		if m.recapping {
+			return m.finish()
+		}
+		// Mastery rounds follow the last loop and aren't loops themselves
+		if m.masteryRounds == 0 {
+			m.loopsCompleted++
+		}
+		// Loops == 0 means repeat until the user quits
+		lastLoop := m.masteryRounds > 0 || m.config.Loops != 0 && m.loopsCompleted >= m.config.Loops
+		if lastLoop {
+			if !m.startMasteryRound() && !m.startRecap() {
+				return m.finish()
+			}
+		} else {
+			m.words = reorderWords(m.config, m.wordList)
+			m.wordIndex = 0
+			m.correctWords = []string{}
+		}
 	}
 	
 	word := m.words[m.wordIndex]
 	if word == "" {
-		return tea.Quit
+		return m.finish()
 	}
 	
+	m.intro = false
 	m.currentWord = word
 	m.inputText = ""
 	m.inputError = ""
+	m.confirming = false
 	m.showInput = false
 	m.dialogState = dialogHidden
+	
+	if m.config.Mode == modeMemory {
+		// Show the word instead of speaking it, then hide it again
+		m.flashing = true
+		m.updateViewportContent()
+		attempt := m.submissions
+		return tea.Tick(m.config.FlashDuration, func(time.Time) tea.Msg {
+			return flashDoneMsg{attempt: attempt}
+		})
+	}
+	if m.config.Mode == modeUnscramble {
+		// A seed per word, so --seed makes the scrambles reproducible too
+		m.scrambled = scrambleLetters(m.target(), rng.Int63())
+	}
+	if m.config.Mode == modeChoose {
+		m.choices = m.choicesFor(m.currentEntry())
+		m.choiceIndex = 0
+	}
 	m.updateViewportContent()
 	
-	// Speak the word
+	// Announce the session once before its first word (SpeakIntro)
+	announcement := ""
+	if m.config.SpeakIntro && !m.introSpoken {
+		m.introSpoken = true
+		announcement = tr(m.localizer, "PracticeInstructions", map[string]interface{}{"Count": m.originalCount})
+	}
+	
+	// Speak the word (with its article, if any) in its own language
+	m.wordRate = jitteredRate(m.config.RateJitter)
+	spoken := m.currentEntry().spokenText()
+	language := m.wordLanguage()
+	rate := m.wordRate
 	return func() tea.Msg {
-		if err := speakWord(word, m.language); err != nil {
-			// Continue even if TTS fails
+		if announcement != "" {
+			_ = m.speaker.Speak(announcement, m.language, defaultRate)
+		}
+		// Continue even if TTS fails; the failure is counted, not fatal
+		err := speakPhrase(spoken, language, rate, m.config.ChunkPhrases, m.speaker)
+		return speakWordMsg{err: err}
+	}
+}
+
+// pendingAnswer returns the answer as it stands when time runs out: the
+// highlighted option in choose mode, otherwise what was typed
+func (m *appModel) pendingAnswer() string {
+	if m.config.Mode == modeChoose && m.choiceIndex < len(m.choices) {
+		return m.choices[m.choiceIndex]
+	}
+	return strings.TrimSpace(m.inputText)
+}
+
+// choicesFor returns the options of the choose mode for entry: its target
+// and distractors that would not be accepted, in random order
+func (m *appModel) choicesFor(entry WordEntry) []string {
+	target := entry.target(m.config.RequireArticle)
+	answers := entry.acceptedAnswers(m.config.RequireArticle)
+	language := entry.languageOr(m.language)
+	accepted := func(candidate string) bool {
+		_, correct := matchAnswer(candidate, answers, language, m.config)
+		return correct
+	}
+	
+
+	var pool []string
+	for _, word := range m.wordList {
+		other, ok := m.entries[word]
+		if !ok {
+			other = WordEntry{Word: word}
+		}
+		pool = append(pool, other.target(m.config.RequireArticle))
+	}
+	distractors := generateDistractors(target, pool, m.config.Choices-1, accepted)
+	return shuffleWords(append(distractors, target))
+}
+
+// renderChoices renders the options of the choose mode, one per line, with
+// the selected one marked
+func (m appModel) renderChoices() string {
+	lines := make([]string, len(m.choices))
+	for i, choice := range m.choices {
+		if i == m.choiceIndex {
+			lines[i] = revealStyle.Render("▸ " + choice)
+		} else {
+			lines[i] = "  " + choice
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// flashDoneMsg is sent when a word in memory mode has been shown long enough
+type flashDoneMsg struct {
+	attempt int // submissions when the word was shown
+}
+
+// resetSession starts the whole list over from scratch with a fresh
+// order, as if the app had just been started (without the intro)
+func (m *appModel) resetSession() tea.Cmd {
+	m.words = reorderWords(m.config, m.wordList)
+	m.wordIndex = 0
+	m.loopsCompleted = 0
+	m.resetStats()
+	m.transcript = nil
+	m.introSpoken = false
+	m.warmingUp = false
+	m.recapping = false
+	m.finished = false
+	m.stoppedEarly = false
+	m.mastered = false
+	m.previewing = false
+	m.flashing = false
+	m.pausing = false
+	m.dialogState = dialogHidden
+	m.dialogDiff = ""
+	m.revealWord = false
+	m.givenUp = false
+	m.spelling = false
+	m.lastInput = ""
+	m.notice = ""
+	return m.startNextWord()
+}
+
+// resetStats clears the counters, streaks and per-word results
+func (m *appModel) resetStats() {
+	m.correctCount = 0
+	m.firstTryCorrect = 0
+	m.score = 0
+	m.correctWords = []string{}
+	m.totalAttempts = 0
+	m.currentStreak = 0
+	m.bestStreak = 0
+	m.attempts = make(map[string]int)
+	m.misses = make(map[string]int)
+	m.missedDuringSession = make(map[string]bool)
+	m.recapCount = 0
+	m.recapAttempts = 0
+	m.recapCorrect = 0
+	m.masteredWords = make(map[string]bool)
+	m.masteryRounds = 0
+	m.retries = 0
+}
+
+// startWarmup puts a few untracked words in front of the graded session
+// Must be called before the program starts
+func (m *appModel) startWarmup(words []string) {
+	if len(words) == 0 {
+		return
+	}
+	m.gradedWords = m.words
+	m.words = words
+	m.warmingUp = true
+	m.warmupCount = len(words)
+}
+
+// endWarmup forgets the warm-up results and starts the graded words
+func (m *appModel) endWarmup() {
+	m.resetStats()
+	m.warmingUp = false
+	m.words = m.gradedWords
+	m.wordIndex = 0
+}
+
+// startRecap queues the words that were missed during the session for one
+// more round, reshuffled; it reports false if there is nothing to recap
+func (m *appModel) startRecap() bool {
+	if !m.config.RecapMissed {
+		return false
+	}
+	// The missed words stay in missedDuringSession for the summary
+	var missed []string
+	queued := make(map[string]bool)
+	for _, word := range m.wordList {
+		if m.missedDuringSession[word] && !queued[word] {
+			missed = append(missed, word)
+			queued[word] = true // Recap duplicates only once
+		}
+	}
+	if len(missed) == 0 {
+		return false
+	}
+	m.recapping = true
+	m.recapCount = len(missed)
+	m.words = reorderWords(m.config, missed)
+	m.wordIndex = 0
+	m.correctWords = []string{}
+	return true
+}
+
+// startMasteryRound queues the words that were never spelled correctly for
+// another round, reshuffled; it reports false once every word is mastered
+// Words given up on after MaxAttempts misses are left out, so a word the
+// learner can't spell doesn't keep the session going forever
+func (m *appModel) startMasteryRound() bool {
+	if !m.config.MasteryLoop {
+		return false
+	}
+	var open []string
+	for _, word := range m.wordList {
+		givenUp := m.config.MaxAttempts > 0 && m.misses[word] >= m.config.MaxAttempts
+		if !m.masteredWords[word] && !givenUp {
+			open = append(open, word)
 		}
-		return speakWordMsg{}
 	}
+	if len(open) == 0 {
+		return false
+	}
+	m.masteryRounds++
+	m.words = reorderWords(m.config, open)
+	m.wordIndex = 0
+	m.correctWords = []string{}
+	return true
+}
+
+// sessionResult collects the outcome of the session for the history
+// The TUI doesn't keep every typed answer, so Answers stays empty
+func (m appModel) sessionResult() SessionResult {
+	result := SessionResult{
+		WordCount:     m.originalCount,
+		TotalAttempts: m.totalAttempts,
+		CorrectCount:  m.correctCount,
+		BestStreak:    m.bestStreak,
+		Completed:     m.finished && !m.stoppedEarly,
+	}
+	for _, word := range m.wordList {
+		if m.attempts[word] == 0 {
+			continue // Not reached before the session ended
+		}
+		result.Words = append(result.Words, WordResult{
+			Word:     word,
+			Attempts: m.attempts[word],
+			Misses:   m.misses[word],
+			Answers:  []string{},
+			Correct:  m.attempts[word] > m.misses[word],
+		})
+	}
+	result.finish()
+	return result
+}
+
+// sessionState captures the progress for resuming the session later
+// A word whose correct answer is still showing in the dialog is done;
+// after a wrong answer it stays first in the queue
+func (m appModel) sessionState() SessionState {
+	var remaining []string
+	if m.warmingUp {
+		remaining = m.gradedWords // The warm-up doesn't need to be resumed
+	} else if m.wordIndex < len(m.words) {
+		remaining = m.words[m.wordIndex:]
+		if m.dialogState == dialogShowing && m.dialogType != dialogIncorrect {
+			remaining = remaining[1:]
+		}
+	}
+	var missed, mastered []string
+	for _, word := range m.wordList {
+		if m.missedDuringSession[word] {
+			missed = append(missed, word)
+		}
+		if m.masteredWords[word] {
+			mastered = append(mastered, word)
+		}
+	}
+	return SessionState{
+		Words:           append([]string{}, remaining...),
+		WordList:        m.wordList,
+		OriginalCount:   m.originalCount,
+		LoopsCompleted:  m.loopsCompleted,
+		CorrectCount:    m.correctCount,
+		FirstTryCorrect: m.firstTryCorrect,
+		TotalAttempts:   m.totalAttempts,
+		Score:           m.score,
+		BestStreak:      m.bestStreak,
+		CorrectWords:    m.correctWords,
+		Attempts:        m.attempts,
+		Misses:          m.misses,
+		Missed:          missed,
+		Recapping:       m.recapping,
+		Mastered:        mastered,
+		MasteryRounds:   m.masteryRounds,
+	}
+}
+
+// resumeSession continues a saved session instead of starting over and
+// reports whether it did; words that are no longer in the config are left
+// out, so a session of a completely different list starts over
+// Must be called before the program starts
+func (m *appModel) resumeSession(state SessionState) bool {
+	known := func(words []string) []string {
+		kept := []string{}
+		for _, word := range words {
+			if _, ok := m.entries[word]; ok {
+				kept = append(kept, word)
+			}
+		}
+		return kept
+	}
+	wordList := known(state.WordList)
+	if len(wordList) == 0 {
+		return false
+	}
+	m.words = known(state.Words)
+	m.wordList = wordList
+	m.wordIndex = 0
+	m.originalCount = state.OriginalCount
+	m.loopsCompleted = state.LoopsCompleted
+	m.correctCount = state.CorrectCount
+	m.firstTryCorrect = state.FirstTryCorrect
+	m.totalAttempts = state.TotalAttempts
+	m.score = state.Score
+	m.bestStreak = state.BestStreak
+	m.correctWords = known(state.CorrectWords)
+	if state.Attempts != nil {
+		m.attempts = state.Attempts
+	}
+	if state.Misses != nil {
+		m.misses = state.Misses
+	}
+	for _, word := range known(state.Missed) {
+		m.missedDuringSession[word] = true
+	}
+	m.recapping = state.Recapping
+	for _, word := range known(state.Mastered) {
+		m.masteredWords[word] = true
+	}
+	m.masteryRounds = state.MasteryRounds
+	return true
+}
+
+// finish ends the practice and shows the summary screen
+func (m *appModel) finish() tea.Cmd {
+	m.finished = true
+	m.showInput = false
+	m.flashing = false
+	m.pausing = false
+	m.dialogState = dialogHidden
+	return nil
+}
+
+// stopEarly ends the practice before the list is done and shows the
+// summary with the partial results; a second keypress then quits
+func (m *appModel) stopEarly() tea.Cmd {
+	m.stoppedEarly = true
+	return m.finish()
 }
 
 // speakWordMsg is sent when word has been spoken
-type speakWordMsg struct{}
+type speakWordMsg struct {
+	err error // Why the word couldn't be spoken, if it failed
+}
+
+// reachedTargetAccuracy reports whether the learner has shown mastery:
+// enough answers, with an accuracy at or above the target so far
+func (m *appModel) reachedTargetAccuracy() bool {
+	if m.config.TargetAccuracy == 0 || m.warmingUp || m.totalAttempts == 0 {
+		return false
+	}
+	if m.totalAttempts < m.config.MinWords {
+		return false
+	}
+	return m.correctCount*100/m.totalAttempts >= m.config.TargetAccuracy
+}
 
 // handleDialogClose handles closing the dialog and moving to next word
 func (m *appModel) handleDialogClose() tea.Cmd {
-	// If word was incorrect, add it back to the end of the queue
-	if m.dialogType == dialogIncorrect && m.currentWord != "" {
-		m.words = append(m.words, m.currentWord)
+	// If word was incorrect, add it back to the queue: right after the
+	// current position for immediate retries, at the end otherwise.
+	// Single-pass sessions present every word exactly once, and words
+	// that were given up on aren't asked again either.
+	// Answers the teacher accepted count as correct and move on, too,
+	// and warm-up words are only a single pass.
+	if m.dialogType == dialogIncorrect && m.currentWord != "" && !m.config.SinglePass && !m.givenUp && !m.manualOverride && !m.warmingUp {
+		immediate := retryImmediately(m.config, m.retries)
+		m.words = requeue(m.words, m.wordIndex, m.currentWord, immediate)
+		if immediate {
+			m.retries++
+		} else {
+			m.retries = 0
+		}
+	} else {
+		m.retries = 0
 	}
 	
 	m.dialogState = dialogHidden
 	m.dialogDiff = ""
+	m.revealWord = false
+	m.givenUp = false
+	m.spelling = false
+	m.manualOverride = false
+	m.lastInput = ""
 	m.wordIndex++
 	
+	if m.reachedTargetAccuracy() {
+		m.mastered = true
+		return m.finish()
+	}
+	
+	// Take a short breath before the next word; the summary after the
+	// last word needs no pause
+	if m.config.InterWordPause > 0 && m.wordIndex < len(m.words) {
+		m.pausing = true
+		m.showInput = false
+		m.updateViewportContent()
+		attempt := m.submissions
+		return tea.Tick(m.config.InterWordPause, func(time.Time) tea.Msg {
+			return interWordPauseMsg{attempt: attempt}
+		})
+	}
 	return m.startNextWord()
 }
+
+// interWordPauseMsg is sent when the pause between two words is over
+type interWordPauseMsg struct {
+	attempt int // submissions when the pause was started
+}
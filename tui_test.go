@@ -1,23 +1,54 @@
 package main
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
+	"unicode/utf8"
 
 	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
+// setupTestConfig creates a minimal English config for testing
+func setupTestConfig() *Config {
+	config := defaultConfig()
+	config.Language = "en"
+	config.SkipIntro = true // Most tests start practicing right away
+	config.InterWordPause = 0 // and move to the next word without waiting
+	return &config
+}
+
+// recordingSpeaker is a fake Speaker that records what it was asked to say
+type recordingSpeaker struct {
+	texts []string
+	rates []int
+	langs []string
+}
+
+func (r *recordingSpeaker) Speak(text, langCode string, rate int) error {
+	r.texts = append(r.texts, text)
+	r.rates = append(r.rates, rate)
+	r.langs = append(r.langs, langCode)
+	return nil
+}
+
 // setupTestTUI creates a test appModel with minimal setup
 func setupTestTUI() appModel {
 	localizer, _ := initI18n("en")
 	words := []string{"Haus", "Buch", "Schule"}
-	return initialAppModel(localizer, "en", words)
+	return initialAppModel(localizer, setupTestConfig(), words)
 }
 
 // TestTitleBarRendering tests the title bar rendering
 func TestTitleBarRendering(t *testing.T) {
 	localizer, _ := initI18n("en")
-	model := initialAppModel(localizer, "en", []string{"Haus", "Buch"})
+	model := initialAppModel(localizer, setupTestConfig(), []string{"Haus", "Buch"})
 	model.width = 80
 	model.height = 24
 	model.wordIndex = 0
@@ -45,7 +76,7 @@ func TestTitleBarRendering(t *testing.T) {
 // TestTitleBarWithCorrectWords tests title bar with correctly spelled words
 func TestTitleBarWithCorrectWords(t *testing.T) {
 	localizer, _ := initI18n("en")
-	model := initialAppModel(localizer, "en", []string{"Haus", "Buch"})
+	model := initialAppModel(localizer, setupTestConfig(), []string{"Haus", "Buch"})
 	model.width = 80
 	model.correctWords = []string{"Haus"}
 	model.correctCount = 1
@@ -63,7 +94,7 @@ func TestTitleBarWithCorrectWords(t *testing.T) {
 // TestDialogRendering tests dialog rendering
 func TestDialogRendering(t *testing.T) {
 	localizer, _ := initI18n("en")
-	model := initialAppModel(localizer, "en", []string{"Haus"})
+	model := initialAppModel(localizer, setupTestConfig(), []string{"Haus"})
 	model.dialogState = dialogShowing
 	model.dialogType = dialogCorrect
 	model.dialogDiff = ""
@@ -84,7 +115,7 @@ func TestDialogRendering(t *testing.T) {
 // TestDialogWithDiff tests dialog with diff content
 func TestDialogWithDiff(t *testing.T) {
 	localizer, _ := initI18n("en")
-	model := initialAppModel(localizer, "en", []string{"Haus"})
+	model := initialAppModel(localizer, setupTestConfig(), []string{"Haus"})
 	model.dialogState = dialogShowing
 	model.dialogType = dialogIncorrect
 	model.dialogDiff = formatWordDiff("Hau", "Haus", localizer)
@@ -109,7 +140,7 @@ func TestDialogWithDiff(t *testing.T) {
 // TestViewWithDialog tests that title bar is visible when dialog is shown
 func TestViewWithDialog(t *testing.T) {
 	localizer, _ := initI18n("en")
-	model := initialAppModel(localizer, "en", []string{"Haus"})
+	model := initialAppModel(localizer, setupTestConfig(), []string{"Haus"})
 	model.width = 80
 	model.height = 24
 	model.ready = true
@@ -137,7 +168,7 @@ func TestViewWithDialog(t *testing.T) {
 // TestViewWithoutDialog tests normal view rendering
 func TestViewWithoutDialog(t *testing.T) {
 	localizer, _ := initI18n("en")
-	model := initialAppModel(localizer, "en", []string{"Haus"})
+	model := initialAppModel(localizer, setupTestConfig(), []string{"Haus"})
 	model.width = 80
 	model.height = 24
 	model.ready = true
@@ -165,7 +196,7 @@ func TestViewWithoutDialog(t *testing.T) {
 // TestTitleBarWidthCalculation tests that title bar width accounts for borders
 func TestTitleBarWidthCalculation(t *testing.T) {
 	localizer, _ := initI18n("en")
-	model := initialAppModel(localizer, "en", []string{"Haus"})
+	model := initialAppModel(localizer, setupTestConfig(), []string{"Haus"})
 	model.width = 80
 
 	titleBar := model.renderTitleBar()
@@ -193,7 +224,7 @@ func TestTitleBarWidthCalculation(t *testing.T) {
 // TestDialogCentering tests that dialog is centered
 func TestDialogCentering(t *testing.T) {
 	localizer, _ := initI18n("en")
-	model := initialAppModel(localizer, "en", []string{"Haus"})
+	model := initialAppModel(localizer, setupTestConfig(), []string{"Haus"})
 	model.width = 80
 	model.height = 24
 	model.ready = true
@@ -228,7 +259,7 @@ func TestDialogCentering(t *testing.T) {
 // TestCurrentWordPreservation tests that currentWord is preserved during validation
 func TestCurrentWordPreservation(t *testing.T) {
 	localizer, _ := initI18n("en")
-	model := initialAppModel(localizer, "en", []string{"Haus", "Buch"})
+	model := initialAppModel(localizer, setupTestConfig(), []string{"Haus", "Buch"})
 	model.currentWord = "Haus"
 	model.wordIndex = 0
 
@@ -254,7 +285,7 @@ func TestCurrentWordPreservation(t *testing.T) {
 // TestViewportContentUpdate tests viewport content updates
 func TestViewportContentUpdate(t *testing.T) {
 	localizer, _ := initI18n("en")
-	model := initialAppModel(localizer, "en", []string{"Haus"})
+	model := initialAppModel(localizer, setupTestConfig(), []string{"Haus"})
 	model.width = 80
 	model.height = 24
 	model.viewport = viewport.New(model.width, model.height-3)
@@ -279,7 +310,7 @@ func TestViewportContentUpdate(t *testing.T) {
 // TestViewportContentWithError tests viewport with error message
 func TestViewportContentWithError(t *testing.T) {
 	localizer, _ := initI18n("en")
-	model := initialAppModel(localizer, "en", []string{"Haus"})
+	model := initialAppModel(localizer, setupTestConfig(), []string{"Haus"})
 	model.width = 80
 	model.height = 24
 	model.viewport = viewport.New(model.width, model.height-3)
@@ -294,3 +325,2098 @@ func TestViewportContentWithError(t *testing.T) {
 		t.Error("Viewport should contain error message")
 	}
 }
+
+// TestTitleBarWithCustomTitle tests that the config title replaces the default title
+func TestTitleBarWithCustomTitle(t *testing.T) {
+	localizer, _ := initI18n("en")
+	config := setupTestConfig()
+	config.Title = "Week 3: ck and tz"
+	model := initialAppModel(localizer, config, []string{"Haus"})
+	model.width = 120
+
+	titleBar := model.renderTitleBar()
+
+	// Should contain the custom title instead of the default one
+	if !strings.Contains(titleBar, "Week 3: ck and tz") {
+		t.Errorf("Title bar should contain custom title, got:\n%s", titleBar)
+	}
+	if strings.Contains(titleBar, "Dictation Practice") {
+		t.Error("Title bar should not contain default title when custom title is set")
+	}
+}
+
+// TestTitleBarDefaultTitle tests the fallback to the localized title
+func TestTitleBarDefaultTitle(t *testing.T) {
+	localizer, _ := initI18n("en")
+	model := initialAppModel(localizer, setupTestConfig(), []string{"Haus"})
+	model.width = 120
+
+	titleBar := model.renderTitleBar()
+
+	if !strings.Contains(titleBar, "Dictation Practice") {
+		t.Errorf("Title bar should fall back to localized title, got:\n%s", titleBar)
+	}
+}
+
+// TestStreakCounting tests that streaks increment on correct and reset on incorrect answers
+func TestStreakCounting(t *testing.T) {
+	model := setupTestTUI()
+
+	model.currentWord = "Haus"
+	_, _ = model.validateInput("Haus")
+	model.currentWord = "Buch"
+	_, _ = model.validateInput("Buch")
+
+	if model.currentStreak != 2 {
+		t.Errorf("currentStreak = %d, want 2", model.currentStreak)
+	}
+
+	model.currentWord = "Schule"
+	_, _ = model.validateInput("Schul")
+
+	if model.currentStreak != 0 {
+		t.Errorf("currentStreak should reset on incorrect answer, got %d", model.currentStreak)
+	}
+	if model.bestStreak != 2 {
+		t.Errorf("bestStreak = %d, want 2", model.bestStreak)
+	}
+	if model.totalAttempts != 3 {
+		t.Errorf("totalAttempts = %d, want 3", model.totalAttempts)
+	}
+}
+
+// TestStreakMilestones tests the milestone thresholds
+func TestStreakMilestones(t *testing.T) {
+	for _, milestone := range streakMilestones {
+		if !isStreakMilestone(milestone) {
+			t.Errorf("isStreakMilestone(%d) should be true", milestone)
+		}
+	}
+	if isStreakMilestone(3) {
+		t.Error("isStreakMilestone(3) should be false")
+	}
+}
+
+// TestStreakMilestoneDialog tests that reaching a milestone shows the celebration dialog
+func TestStreakMilestoneDialog(t *testing.T) {
+	model := setupTestTUI()
+	model.currentStreak = streakMilestones[0] - 1
+	model.currentWord = "Haus"
+
+	_, _ = model.validateInput("Haus")
+
+	if model.dialogType != dialogMilestone {
+		t.Fatalf("dialogType = %v, want dialogMilestone", model.dialogType)
+	}
+	if !strings.Contains(model.renderDialog(), "in a row") {
+		t.Error("Milestone dialog should celebrate the streak")
+	}
+}
+
+// TestSummaryAfterLastWord tests that the summary is shown when the queue is exhausted
+func TestSummaryAfterLastWord(t *testing.T) {
+	localizer, _ := initI18n("en")
+	model := initialAppModel(localizer, setupTestConfig(), []string{"Haus"})
+	model.width = 80
+	model.height = 24
+	model.ready = true
+	model.currentWord = "Haus"
+
+	_, _ = model.validateInput("Haus")
+	_ = model.handleDialogClose()
+
+	if !model.finished {
+		t.Fatal("Model should be finished after the last word")
+	}
+
+	view := model.View()
+	for _, want := range []string{"Practice Complete", "Accuracy: 100%", "Best streak: 1"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Summary should contain %q, got:\n%s", want, view)
+		}
+	}
+}
+
+// TestDialogWithoutDiff tests that listening mode reveals the word without a diff
+func TestDialogWithoutDiff(t *testing.T) {
+	localizer, _ := initI18n("en")
+	config := setupTestConfig()
+	config.ShowDiff = false
+	model := initialAppModel(localizer, config, []string{"Haus"})
+	model.currentWord = "Haus"
+
+	_, _ = model.validateInput("Hau")
+	dialog := model.renderDialog()
+
+	if strings.Contains(dialog, "Differences") || strings.Contains(dialog, "^") {
+		t.Error("Dialog should not contain a diff when ShowDiff is false")
+	}
+	if !strings.Contains(dialog, "Haus") {
+		t.Error("Dialog should still reveal the correct spelling")
+	}
+}
+
+// TestLoopModeRestartsList tests that the list starts over until all loops are done
+func TestLoopModeRestartsList(t *testing.T) {
+	localizer, _ := initI18n("en")
+	config := setupTestConfig()
+	config.Loops = 2
+	model := initialAppModel(localizer, config, []string{"Haus", "Buch"})
+	model.width = 80
+	model.height = 24
+	model.ready = true
+
+	// Spell every word of the first loop correctly
+	for i := 0; i < 2; i++ {
+		model.currentWord = model.words[model.wordIndex]
+		_, _ = model.validateInput(model.currentWord)
+		_ = model.handleDialogClose()
+	}
+
+	if model.finished {
+		t.Fatal("Model should not be finished after the first of two loops")
+	}
+	if model.wordIndex != 0 || model.loopsCompleted != 1 {
+		t.Errorf("wordIndex = %d, loopsCompleted = %d, want 0 and 1", model.wordIndex, model.loopsCompleted)
+	}
+
+	// Second loop
+	for i := 0; i < 2; i++ {
+		model.currentWord = model.words[model.wordIndex]
+		_, _ = model.validateInput(model.currentWord)
+		_ = model.handleDialogClose()
+	}
+
+	if !model.finished {
+		t.Fatal("Model should be finished after the second loop")
+	}
+	if model.correctCount != 4 {
+		t.Errorf("correctCount = %d, want stats accumulated over loops (4)", model.correctCount)
+	}
+	if !strings.Contains(model.View(), "Loops completed: 2") {
+		t.Error("Summary should show the number of completed loops")
+	}
+}
+
+// TestInfiniteLoopMode tests that Loops == 0 never finishes on its own
+func TestInfiniteLoopMode(t *testing.T) {
+	localizer, _ := initI18n("en")
+	config := setupTestConfig()
+	config.Loops = 0
+	model := initialAppModel(localizer, config, []string{"Haus"})
+
+	for i := 0; i < 5; i++ {
+		model.currentWord = model.words[model.wordIndex]
+		_, _ = model.validateInput(model.currentWord)
+		_ = model.handleDialogClose()
+	}
+
+	if model.finished {
+		t.Error("Infinite loop mode should not finish by itself")
+	}
+	if model.loopsCompleted != 5 {
+		t.Errorf("loopsCompleted = %d, want 5", model.loopsCompleted)
+	}
+}
+
+// TestViewportContentWithThemeCursor tests that a configured cursor replaces the default
+func TestViewportContentWithThemeCursor(t *testing.T) {
+	localizer, _ := initI18n("en")
+	config := setupTestConfig()
+	config.Theme.Cursor = "▏"
+	model := initialAppModel(localizer, config, []string{"Haus"})
+	model.viewport = viewport.New(80, 21)
+	model.showInput = true
+	model.inputText = "Ha"
+
+	model.updateViewportContent()
+	content := model.viewport.View()
+
+	if !strings.Contains(content, "Ha▏") {
+		t.Errorf("Viewport should contain configured cursor, got:\n%s", content)
+	}
+	if strings.Contains(content, "█") {
+		t.Error("Viewport should not contain the default cursor")
+	}
+}
+
+// TestDiacriticsOptionalShowsDiff tests that accepted answers without umlauts still show the diff
+func TestDiacriticsOptionalShowsDiff(t *testing.T) {
+	localizer, _ := initI18n("en")
+	config := setupTestConfig()
+	config.DiacriticsOptional = true
+	model := initialAppModel(localizer, config, []string{"Mädchen"})
+	model.currentWord = "Mädchen"
+
+	_, _ = model.validateInput("Madchen")
+
+	if model.dialogType != dialogCorrect {
+		t.Fatal("Answer without umlaut should be accepted when diacritics are optional")
+	}
+	if !strings.Contains(model.dialogDiff, "Mädchen") {
+		t.Error("Dialog should still show the proper spelling with umlaut")
+	}
+}
+
+// TestSlowReplay tests that shift+tab replays the word at the slow rate
+func TestSlowReplay(t *testing.T) {
+	model := setupTestTUI()
+	speaker := &recordingSpeaker{}
+	model.speaker = speaker
+	model.currentWord = "Haus"
+	model.showInput = true
+
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	if cmd == nil {
+		t.Fatal("shift+tab should return a replay command")
+	}
+	cmd()
+
+	if len(speaker.rates) != 1 || speaker.rates[0] != slowRate {
+		t.Errorf("speaker rates = %v, want [%d]", speaker.rates, slowRate)
+	}
+	if speaker.texts[0] != "Haus" {
+		t.Errorf("speaker text = %q, want %q", speaker.texts[0], "Haus")
+	}
+}
+
+// TestSlowReplayHint tests that the slow replay hint is shown in the prompt
+func TestSlowReplayHint(t *testing.T) {
+	model := setupTestTUI()
+	model.viewport = viewport.New(80, 21)
+	model.showInput = true
+
+	model.updateViewportContent()
+
+	if !strings.Contains(model.viewport.View(), "Shift+TAB") {
+		t.Error("Viewport should contain the slow replay hint")
+	}
+}
+
+// TestTerminalTooSmall tests the resize message on narrow terminals
+func TestTerminalTooSmall(t *testing.T) {
+	model := setupTestTUI()
+
+	updated, _ := model.Update(tea.WindowSizeMsg{Width: 40, Height: 24})
+	view := updated.View()
+
+	if !strings.Contains(view, "enlarge your terminal") {
+		t.Errorf("View should ask to enlarge the terminal, got:\n%s", view)
+	}
+
+	// Growing the window resumes the normal view
+	updated, _ = updated.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	view = updated.View()
+
+	if strings.Contains(view, "enlarge your terminal") {
+		t.Error("View should resume once the terminal is large enough")
+	}
+	if !strings.Contains(view, "🔊") {
+		t.Error("View should show the title bar again")
+	}
+}
+
+// TestQuitShowsPartialSummary tests that q shows the summary before exiting
+func TestQuitShowsPartialSummary(t *testing.T) {
+	model := setupTestTUI()
+	model.width = 80
+	model.height = 24
+	model.ready = true
+	model.showInput = true
+	model.currentWord = "Haus"
+	_, _ = model.validateInput("Haus")
+
+	updated, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	if cmd != nil {
+		t.Error("q should not quit immediately")
+	}
+
+	finished := updated.(appModel)
+	if !finished.finished || !finished.stoppedEarly {
+		t.Fatal("q should show the summary")
+	}
+	view := finished.View()
+	if !strings.Contains(view, "stopped early") || !strings.Contains(view, "Total attempts: 1") {
+		t.Errorf("Summary should show partial results, got:\n%s", view)
+	}
+
+	// Second keypress quits
+	_, cmd = finished.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	if cmd == nil {
+		t.Fatal("Second q should quit")
+	}
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Error("Second q should return tea.Quit")
+	}
+}
+
+// TestQuitKeys tests the resulting state of each quit key in each state
+func TestQuitKeys(t *testing.T) {
+	keys := map[string]tea.KeyMsg{
+		"q":      {Type: tea.KeyRunes, Runes: []rune("q")},
+		"ctrl+c": {Type: tea.KeyCtrlC},
+		"esc":    {Type: tea.KeyEsc},
+		"ctrl+q": {Type: tea.KeyCtrlQ},
+	}
+	tests := []struct {
+		state       string
+		key         string
+		wantQuit    bool // tea.Quit right away
+		wantSummary bool // Summary screen with partial results
+	}{
+		{"dialog", "q", false, true},
+		{"dialog", "ctrl+c", true, false},
+		{"dialog", "esc", true, false},
+		{"dialog", "ctrl+q", false, true},
+		{"input", "q", false, false}, // Typed as a letter
+		{"input", "ctrl+c", true, false},
+		{"input", "esc", true, false},
+		{"input", "ctrl+q", false, true},
+		{"waiting", "q", false, true},
+		{"waiting", "ctrl+c", true, false},
+		{"waiting", "esc", true, false},
+		{"waiting", "ctrl+q", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.state+"/"+tt.key, func(t *testing.T) {
+			model := setupTestTUI()
+			model.startNextWord()
+			switch tt.state {
+			case "dialog":
+				model.validateInput("Haus")
+			case "input":
+				model.showInput = true
+			}
+
+			updated, cmd := model.Update(keys[tt.key])
+			result := updated.(appModel)
+			quit := false
+			if cmd != nil {
+				_, quit = cmd().(tea.QuitMsg)
+			}
+			if quit != tt.wantQuit {
+				t.Errorf("quit = %v, want %v", quit, tt.wantQuit)
+			}
+			if result.finished != tt.wantSummary {
+				t.Errorf("summary = %v, want %v", result.finished, tt.wantSummary)
+			}
+			// Quitting right away doesn't save the session or the history
+			if result.aborted != tt.wantQuit {
+				t.Errorf("aborted = %v, want %v", result.aborted, tt.wantQuit)
+			}
+			if tt.state == "input" && tt.key == "q" && result.inputText != "q" {
+				t.Errorf("inputText = %q, want q typed", result.inputText)
+			}
+		})
+	}
+}
+
+// TestAutoRevealAfterMisses tests that the spelling is revealed after repeated misses
+func TestAutoRevealAfterMisses(t *testing.T) {
+	localizer, _ := initI18n("en")
+	config := setupTestConfig()
+	config.AutoRevealAfter = 2
+	model := initialAppModel(localizer, config, []string{"Haus", "Buch"})
+	model.currentWord = "Haus"
+
+	_, _ = model.validateInput("Hau")
+	if model.revealWord {
+		t.Fatal("Word should not be revealed after the first miss")
+	}
+	_ = model.handleDialogClose()
+
+	// The word comes back from the end of the queue
+	model.currentWord = "Haus"
+	_, _ = model.validateInput("Hauss")
+
+	if !model.revealWord {
+		t.Fatal("Word should be revealed after the second miss")
+	}
+	if model.attempts["Haus"] != 2 {
+		t.Errorf("attempts[Haus] = %d, want 2", model.attempts["Haus"])
+	}
+	if !strings.Contains(model.renderDialog(), "Here is how it is spelled") {
+		t.Error("Dialog should contain the reveal hint")
+	}
+}
+
+// TestAutoRevealDisabled tests that the default config never reveals words
+func TestAutoRevealDisabled(t *testing.T) {
+	model := setupTestTUI()
+	model.currentWord = "Haus"
+
+	for i := 0; i < 3; i++ {
+		_, _ = model.validateInput("Hau")
+	}
+
+	if model.revealWord {
+		t.Error("Word should not be revealed when AutoRevealAfter is 0")
+	}
+}
+
+// TestViewportContentRTL tests that right-to-left prompts put the cursor before the text
+func TestViewportContentRTL(t *testing.T) {
+	localizer, _ := initI18n("en")
+	config := setupTestConfig()
+	config.Language = "he"
+	model := initialAppModel(localizer, config, []string{"שלום"})
+	model.viewport = viewport.New(80, 21)
+	model.showInput = true
+	model.inputText = "של"
+
+	model.updateViewportContent()
+	content := model.viewport.View()
+
+	if !strings.Contains(content, "█של") {
+		t.Errorf("Cursor should precede right-to-left input, got:\n%s", content)
+	}
+}
+
+// TestInputLimitsFitLongestPhrase tests that the input accepts the longest phrase
+func TestInputLimitsFitLongestPhrase(t *testing.T) {
+	localizer, _ := initI18n("en")
+	phrase := strings.Repeat("abcdefghi ", 12) // 120 characters
+	config := setupTestConfig()
+	model := initialAppModel(localizer, config, []string{"Haus", phrase})
+	model.viewport = viewport.New(80, 20)
+	model.showInput = true
+
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(phrase)})
+	model = updated.(appModel)
+	if model.inputText != phrase {
+		t.Errorf("input should accommodate the %d-char phrase, got %d chars", len(phrase), len(model.inputText))
+	}
+}
+
+// TestInputLimitsShortWords tests that typing stops after the longest word
+// plus the margin
+func TestInputLimitsShortWords(t *testing.T) {
+	localizer, _ := initI18n("en")
+	model := initialAppModel(localizer, setupTestConfig(), []string{"Haus", "Mädchen"})
+	model.viewport = viewport.New(80, 20)
+	model.showInput = true
+
+	// Mädchen has 7 runes (but 8 bytes)
+	if model.charLimit != 7+inputMargin {
+		t.Errorf("charLimit = %d, want %d", model.charLimit, 7+inputMargin)
+	}
+	for i := 0; i < 30; i++ {
+		updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("ä")})
+		model = updated.(appModel)
+	}
+	if got := utf8.RuneCountInString(model.inputText); got != model.charLimit {
+		t.Errorf("typed %d characters, want the limit of %d", got, model.charLimit)
+	}
+}
+
+// TestMaskedInput tests that masked input never shows the raw typed text
+func TestMaskedInput(t *testing.T) {
+	localizer, _ := initI18n("en")
+	config := setupTestConfig()
+	config.MaskInput = true
+	model := initialAppModel(localizer, config, []string{"Mädchen"})
+	model.viewport = viewport.New(80, 21)
+	model.showInput = true
+	model.inputText = "Mädch"
+
+	model.updateViewportContent()
+	content := model.viewport.View()
+
+	if strings.Contains(content, "Mädch") {
+		t.Errorf("Masked viewport should not contain the typed text, got:\n%s", content)
+	}
+	if !strings.Contains(content, "•••••") {
+		t.Errorf("Masked viewport should show one dot per character, got:\n%s", content)
+	}
+
+}
+
+// setupArticleTUI creates a model practicing "das Haus"
+func setupArticleTUI(requireArticle bool) (appModel, *recordingSpeaker) {
+	localizer, _ := initI18n("en")
+	config := setupTestConfig()
+	config.Language = "de"
+	config.RequireArticle = requireArticle
+	config.Words = []WordEntry{{Word: "Haus", Article: "das"}}
+	model := initialAppModel(localizer, config, []string{"Haus"})
+	speaker := &recordingSpeaker{}
+	model.speaker = speaker
+	return model, speaker
+}
+
+// TestArticleNotRequired tests that the article is spoken but only the word is checked
+func TestArticleNotRequired(t *testing.T) {
+	model, speaker := setupArticleTUI(false)
+
+	cmd := model.startNextWord()
+	cmd()
+	if len(speaker.texts) != 1 || speaker.texts[0] != "das Haus" {
+		t.Errorf("speaker texts = %v, want [das Haus]", speaker.texts)
+	}
+
+	_, _ = model.validateInput("Haus")
+	if model.dialogType != dialogCorrect {
+		t.Error("Word without article should be correct when the article is not required")
+	}
+}
+
+// TestArticleRequired tests that the article must be typed and is part of the diff
+func TestArticleRequired(t *testing.T) {
+	model, _ := setupArticleTUI(true)
+	_ = model.startNextWord()
+
+	_, _ = model.validateInput("Haus")
+	if model.dialogType != dialogIncorrect {
+		t.Fatal("Word without article should be incorrect when the article is required")
+	}
+	if !strings.Contains(model.dialogDiff, "das Haus") {
+		t.Errorf("Diff should target the word with article, got:\n%s", model.dialogDiff)
+	}
+
+	_ = model.handleDialogClose()
+	_, _ = model.validateInput("das Haus")
+	if model.dialogType != dialogCorrect {
+		t.Error("Word with article should be correct when the article is required")
+	}
+}
+
+// answerCurrentWord submits an answer for the word at the current queue position
+func answerCurrentWord(model *appModel, answer string) {
+	model.currentWord = model.words[model.wordIndex]
+	_, _ = model.validateInput(answer)
+	_ = model.handleDialogClose()
+}
+
+// TestRequeueRetryMode tests that misspelled words move to the end of the queue
+func TestRequeueRetryMode(t *testing.T) {
+	localizer, _ := initI18n("en")
+	model := initialAppModel(localizer, setupTestConfig(), []string{"Haus", "Buch", "Schule"})
+
+	answerCurrentWord(&model, "Hau")
+
+	want := "Haus,Buch,Schule,Haus"
+	if got := strings.Join(model.words, ","); got != want {
+		t.Errorf("queue = %s, want %s", got, want)
+	}
+	if model.currentWord != "Buch" {
+		t.Errorf("next word = %q, want %q", model.currentWord, "Buch")
+	}
+}
+
+// TestImmediateRetryMode tests that misspelled words are asked again right away
+func TestImmediateRetryMode(t *testing.T) {
+	localizer, _ := initI18n("en")
+	config := setupTestConfig()
+	config.RetryMode = retryImmediate
+	config.ImmediateRetries = 2
+	model := initialAppModel(localizer, config, []string{"Haus", "Buch"})
+
+	// Two immediate retries ...
+	answerCurrentWord(&model, "Hau")
+	if model.currentWord != "Haus" {
+		t.Fatalf("next word = %q, want immediate retry of Haus", model.currentWord)
+	}
+	answerCurrentWord(&model, "Hau")
+	if model.currentWord != "Haus" {
+		t.Fatalf("next word = %q, want second immediate retry of Haus", model.currentWord)
+	}
+
+	// ... then the word moves to the end of the queue
+	answerCurrentWord(&model, "Hau")
+	if model.currentWord != "Buch" {
+		t.Errorf("next word = %q, want Buch after the retry limit", model.currentWord)
+	}
+	want := "Haus,Haus,Haus,Buch,Haus"
+	if got := strings.Join(model.words, ","); got != want {
+		t.Errorf("queue = %s, want %s", got, want)
+	}
+}
+
+// TestSoundEffectsOffByDefault tests that the dialog only plays sounds when enabled
+func TestSoundEffectsOffByDefault(t *testing.T) {
+	model := setupTestTUI()
+	model.currentWord = "Haus"
+	if _, cmd := model.validateInput("Haus"); cmd != nil {
+		t.Error("validateInput() returned a sound command with sound effects disabled")
+	}
+
+	model = setupTestTUI()
+	model.config.SoundEffects = true
+	model.currentWord = "Haus"
+	if _, cmd := model.validateInput("Hau"); cmd == nil {
+		t.Error("validateInput() returned no sound command with sound effects enabled")
+	}
+}
+
+// TestDialogCompareKeys tests that c and t speak the correct and the typed word
+func TestDialogCompareKeys(t *testing.T) {
+	model := setupTestTUI()
+	speaker := &recordingSpeaker{}
+	model.speaker = speaker
+	model.currentWord = "Haus"
+	model.validateInput("Hauz")
+
+	for _, key := range []string{"c", "t"} {
+		updated, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		if cmd == nil {
+			t.Fatalf("%s should return a speak command", key)
+		}
+		cmd()
+		model = updated.(appModel)
+	}
+
+	want := "Haus,Hauz"
+	if got := strings.Join(speaker.texts, ","); got != want {
+		t.Errorf("spoken = %s, want %s", got, want)
+	}
+	if model.dialogState != dialogShowing {
+		t.Error("c and t should keep the dialog open")
+	}
+	if !strings.Contains(model.renderDialog(), "Press c to hear the correct word") {
+		t.Error("incorrect dialog should show the compare hint")
+	}
+}
+
+// TestRemainingCountsRequeuedWords tests that a requeued word adds to the remaining count
+func TestRemainingCountsRequeuedWords(t *testing.T) {
+	model := setupTestTUI()
+	model.startNextWord()
+	if got := model.remaining(); got != 3 {
+		t.Fatalf("remaining() = %d, want 3", got)
+	}
+
+	// A wrong answer puts the word back at the end of the queue
+	answerCurrentWord(&model, "Hau")
+	if got := model.remaining(); got != 3 {
+		t.Errorf("remaining() after requeue = %d, want 3", got)
+	}
+
+	model.width = 120
+	if !strings.Contains(model.renderTitleBar(), "3 left") {
+		t.Errorf("title bar should show the remaining count, got %q", model.renderTitleBar())
+	}
+}
+
+// TestFirstTryCorrect tests that the summary separates first-try and eventual successes
+func TestFirstTryCorrect(t *testing.T) {
+	model := setupTestTUI()
+	model.startNextWord()
+
+	answerCurrentWord(&model, "Haus")
+	answerCurrentWord(&model, "Buh")
+	answerCurrentWord(&model, "Schule")
+	answerCurrentWord(&model, "Buch")
+
+	if model.firstTryCorrect != 2 || model.correctCount != 3 {
+		t.Errorf("firstTryCorrect = %d, correctCount = %d, want 2 and 3", model.firstTryCorrect, model.correctCount)
+	}
+	if !strings.Contains(model.renderSummary(), "First try: 2, Eventually: 1") {
+		t.Errorf("summary should show first-try stats, got:\n%s", model.renderSummary())
+	}
+}
+
+// TestIntroScreen tests that the model starts on the intro screen and
+// only speaks the first word after Enter
+func TestIntroScreen(t *testing.T) {
+	localizer, _ := initI18n("en")
+	config := setupTestConfig()
+	config.SkipIntro = false
+	model := initialAppModel(localizer, config, []string{"Haus", "Buch"})
+	speaker := &recordingSpeaker{}
+	model.speaker = speaker
+
+	if !model.intro {
+		t.Fatal("model should start in the intro state")
+	}
+	if cmd := model.Init(); cmd != nil {
+		t.Error("Init() should not start the first word while the intro shows")
+	}
+	if !strings.Contains(model.renderIntro(), "Press Enter to begin") {
+		t.Errorf("intro should ask to press Enter, got:\n%s", model.renderIntro())
+	}
+
+	// Other keys keep the intro open
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	model = updated.(appModel)
+	if !model.intro {
+		t.Fatal("only Enter should leave the intro")
+	}
+
+	updated, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = updated.(appModel)
+	if model.intro {
+		t.Error("Enter should leave the intro")
+	}
+	if cmd == nil {
+		t.Fatal("Enter should start the first word")
+	}
+	cmd()
+	if len(speaker.texts) != 1 || speaker.texts[0] != "Haus" {
+		t.Errorf("spoken = %v, want [Haus]", speaker.texts)
+	}
+}
+
+// TestPronunciationIsSpoken tests that the speaker gets the pronunciation
+// while the learner still has to type the word
+func TestPronunciationIsSpoken(t *testing.T) {
+	localizer, _ := initI18n("en")
+	config := setupTestConfig()
+	config.Words = []WordEntry{{Word: "read", Pronunciation: "red"}}
+	model := initialAppModel(localizer, config, []string{"read"})
+	speaker := &recordingSpeaker{}
+	model.speaker = speaker
+
+	model.startNextWord()()
+	model.repeatAudio(slowRate)()
+	if strings.Join(speaker.texts, ",") != "red,red" {
+		t.Errorf("spoken = %v, want the pronunciation [red red]", speaker.texts)
+	}
+
+	model.validateInput("red")
+	if model.dialogType != dialogIncorrect {
+		t.Error("typing the pronunciation should not be accepted")
+	}
+	model.handleDialogClose()
+	model.validateInput("read")
+	if model.dialogType != dialogCorrect {
+		t.Error("typing the word should be accepted")
+	}
+}
+
+// TestAutoAdvance tests that only correct answers schedule an automatic advance
+func TestAutoAdvance(t *testing.T) {
+	model := setupTestTUI()
+	model.config.AutoAdvance = time.Millisecond
+	model.startNextWord()
+
+	_, cmd := model.validateInput("Hau")
+	if cmd != nil {
+		t.Error("incorrect answers should not auto-advance")
+	}
+	model.handleDialogClose()
+
+	_, cmd = model.validateInput("Buch")
+	if cmd == nil {
+		t.Fatal("correct answer should schedule an advance command")
+	}
+	msg := cmd()
+	if _, ok := msg.(autoAdvanceMsg); !ok {
+		t.Fatalf("advance command sent %T, want autoAdvanceMsg", msg)
+	}
+
+	updated, _ := model.Update(msg)
+	model = updated.(appModel)
+	if model.dialogState != dialogHidden || model.currentWord != "Schule" {
+		t.Errorf("auto-advance should move on to Schule, got %q (dialog %v)", model.currentWord, model.dialogState)
+	}
+}
+
+// TestMemoryModeDoesNotSpeak tests that memory mode flashes the word instead of speaking it
+func TestMemoryModeDoesNotSpeak(t *testing.T) {
+	model := setupTestTUI()
+	model.config.Mode = modeMemory
+	model.config.FlashDuration = time.Millisecond
+	speaker := &recordingSpeaker{}
+	model.speaker = speaker
+	model.viewport = viewport.New(80, 20)
+
+	cmd := model.startNextWord()
+	if !model.flashing || model.showInput {
+		t.Fatal("memory mode should show the word before the input")
+	}
+	if !strings.Contains(model.viewport.View(), "Haus") {
+		t.Errorf("flashed word should be visible, got:\n%s", model.viewport.View())
+	}
+
+	updated, _ := model.Update(cmd())
+	model = updated.(appModel)
+	if model.flashing || !model.showInput {
+		t.Error("after the flash the word should be hidden and the input shown")
+	}
+	if strings.Contains(model.viewport.View(), "Haus") {
+		t.Error("the word should be hidden after the flash")
+	}
+
+	// TAB has nothing to replay
+	if _, cmd := model.Update(tea.KeyMsg{Type: tea.KeyTab}); cmd != nil {
+		cmd()
+	}
+	if len(speaker.texts) != 0 {
+		t.Errorf("memory mode spoke %v, want silence", speaker.texts)
+	}
+}
+
+// TestNoShuffleKeepsOrderInLoops tests that later loops keep the config order too
+func TestNoShuffleKeepsOrderInLoops(t *testing.T) {
+	model := setupTestTUI()
+	model.config.NoShuffle = true
+	model.config.Loops = 2
+	model.startNextWord()
+
+	var order []string
+	for i := 0; i < 6; i++ {
+		order = append(order, model.currentWord)
+		answerCurrentWord(&model, model.currentWord)
+	}
+	want := "Haus,Buch,Schule,Haus,Buch,Schule"
+	if got := strings.Join(order, ","); got != want {
+		t.Errorf("order = %s, want %s", got, want)
+	}
+}
+
+// TestPerWordLanguage tests that words with their own language are spoken
+// with the voice of that language
+func TestPerWordLanguage(t *testing.T) {
+	calls := stubRunCommand(t)
+	localizer, _ := initI18n("en")
+	config := setupTestConfig()
+	config.Words = []WordEntry{{Word: "house"}, {Word: "maison", Language: "fr"}}
+	model := initialAppModel(localizer, config, []string{"house", "maison"})
+	model.speaker = sayEngine{}
+
+	model.startNextWord()()
+	answerCurrentWord(&model, "house")
+	model.repeatAudio(defaultRate)()
+
+	if len(*calls) != 2 {
+		t.Fatalf("expected 2 say calls, got %v", *calls)
+	}
+	for i, want := range []string{"say -v Alex -r 180 house", "say -v Thomas -r 180 maison"} {
+		if got := strings.Join((*calls)[i], " "); got != want {
+			t.Errorf("call %d = %q, want %q", i+1, got, want)
+		}
+	}
+}
+
+// TestPreviewBeforePractice tests that the TUI previews the list in config
+// order before practicing the shuffled words, and that a key skips it
+func TestPreviewBeforePractice(t *testing.T) {
+	original := previewPause
+	previewPause = 0
+	t.Cleanup(func() { previewPause = original })
+
+	localizer, _ := initI18n("en")
+	config := setupTestConfig()
+	config.Preview = true
+	config.Words = testEntries("Haus", "Buch", "Schule")
+	model := initialAppModel(localizer, config, []string{"Schule", "Haus", "Buch"})
+	model.viewport = viewport.New(80, 20)
+	speaker := &recordingSpeaker{}
+	model.speaker = speaker
+
+	cmd := model.Init()
+	for i := 0; i < 3; i++ {
+		if !model.previewing {
+			t.Fatalf("preview ended after %d words", i)
+		}
+		updated, next := model.Update(cmd())
+		model = updated.(appModel)
+		cmd = next
+	}
+	if model.previewing || model.currentWord != "Schule" {
+		t.Errorf("after the preview the practice should start with Schule, got %q", model.currentWord)
+	}
+	if got := strings.Join(speaker.texts, ","); got != "Haus,Buch,Schule" {
+		t.Errorf("preview spoke %s, want Haus,Buch,Schule", got)
+	}
+
+	// Skipping with a key
+	model = initialAppModel(localizer, config, []string{"Schule", "Haus", "Buch"})
+	model.viewport = viewport.New(80, 20)
+	model.Init()
+	model.updateViewportContent()
+	if !strings.Contains(model.viewport.View(), "Haus") {
+		t.Errorf("preview should show the first word, got:\n%s", model.viewport.View())
+	}
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	model = updated.(appModel)
+	if model.previewing || model.currentWord != "Schule" {
+		t.Error("a key should skip the preview and start the practice")
+	}
+}
+
+// TestSessionScore tests that the score adds up through a session
+func TestSessionScore(t *testing.T) {
+	model := setupTestTUI()
+	model.config.AutoRevealAfter = 2
+	model.startNextWord()
+
+	answerCurrentWord(&model, "Haus")   // first try: 10
+	answerCurrentWord(&model, "Buh")    // wrong: 0
+	answerCurrentWord(&model, "Schule") // first try: 10
+	answerCurrentWord(&model, "Buh")    // wrong, now revealed: 0
+	answerCurrentWord(&model, "Buch")   // with hint: 2
+
+	if model.score != 22 {
+		t.Errorf("score = %d, want 22", model.score)
+	}
+	if !strings.Contains(model.renderSummary(), "Score: 22") {
+		t.Errorf("summary should show the score, got:\n%s", model.renderSummary())
+	}
+}
+
+// TestExpectedForm tests that the word is spoken but the expected form is checked
+func TestExpectedForm(t *testing.T) {
+	localizer, _ := initI18n("en")
+	config := setupTestConfig()
+	config.Words = []WordEntry{{Word: "Haus", Expected: "Häuser", Instruction: "Type the plural"}}
+	model := initialAppModel(localizer, config, []string{"Haus"})
+	model.viewport = viewport.New(80, 20)
+	speaker := &recordingSpeaker{}
+	model.speaker = speaker
+
+	model.startNextWord()()
+	updated, _ := model.Update(speakWordMsg{})
+	model = updated.(appModel)
+	if len(speaker.texts) != 1 || speaker.texts[0] != "Haus" {
+		t.Errorf("spoken = %v, want [Haus]", speaker.texts)
+	}
+	if !strings.Contains(model.viewport.View(), "Type the plural") {
+		t.Errorf("prompt should show the instruction, got:\n%s", model.viewport.View())
+	}
+
+	model.validateInput("Haus")
+	if model.dialogType != dialogIncorrect {
+		t.Error("the spoken word should not be accepted")
+	}
+	if !strings.Contains(model.dialogDiff, "Häuser") {
+		t.Errorf("diff should target the expected form, got:\n%s", model.dialogDiff)
+	}
+	model.handleDialogClose()
+	model.validateInput("Häuser")
+	if model.dialogType != dialogCorrect {
+		t.Error("the expected form should be accepted")
+	}
+}
+
+// TestSinglePassDoesNotRequeue tests that misspelled words don't grow the queue
+func TestSinglePassDoesNotRequeue(t *testing.T) {
+	model := setupTestTUI()
+	model.config.SinglePass = true
+	model.startNextWord()
+
+	answerCurrentWord(&model, "Hau")
+	if len(model.words) != 3 {
+		t.Errorf("queue has %d words, want 3", len(model.words))
+	}
+	answerCurrentWord(&model, "Buch")
+	answerCurrentWord(&model, "Schule")
+	if !model.finished {
+		t.Fatal("session should end after one pass")
+	}
+	if !strings.Contains(model.renderSummary(), "Accuracy: 66%") {
+		t.Errorf("accuracy should be over the 3 words, got:\n%s", model.renderSummary())
+	}
+}
+
+// TestDialogLongPhraseFitsBox tests that a long phrase doesn't break the dialog box
+func TestDialogLongPhraseFitsBox(t *testing.T) {
+	localizer, _ := initI18n("en")
+	phrase := "Der Hund läuft schnell über die große grüne Wiese am Fluss"
+	model := initialAppModel(localizer, setupTestConfig(), []string{phrase})
+	model.startNextWord()
+	model.validateInput("Der Hund lauft")
+
+	for _, line := range strings.Split(model.renderDialog(), "\n") {
+		// Box width 60 plus the left and right border
+		if w := lipgloss.Width(line); w > 62 {
+			t.Errorf("dialog line is %d cells wide, want at most 62: %q", w, line)
+		}
+	}
+}
+
+// TestSessionLog tests that every answer is logged as one line
+func TestSessionLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.log")
+	logger, file, err := openSessionLogger(path)
+	if err != nil {
+		t.Fatalf("openSessionLogger() error = %v", err)
+	}
+	model := setupTestTUI()
+	model.logger = logger
+	model.startNextWord()
+
+	answerCurrentWord(&model, "Hau")
+	answerCurrentWord(&model, "Buch")
+	file.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read log: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("log has %d lines, want one per attempt:\n%s", len(lines), data)
+	}
+	fields := strings.Split(lines[0], "\t")
+	if len(fields) != 5 || fields[1] != "Haus" || fields[2] != "Hau" || fields[3] != "incorrect" || fields[4] != "1" {
+		t.Errorf("first line = %q, want time, Haus, Hau, incorrect, 1", lines[0])
+	}
+	if !strings.HasSuffix(lines[1], "\tBuch\tBuch\tcorrect\t1") {
+		t.Errorf("second line = %q, want the correct Buch attempt", lines[1])
+	}
+
+	// Unopenable log files are reported
+	if _, _, err := openSessionLogger(filepath.Join(t.TempDir(), "missing", "x.log")); err == nil {
+		t.Error("openSessionLogger() should fail for a missing directory")
+	}
+}
+
+// TestSpeakDefinition tests that Ctrl+D speaks the definition of the current word
+func TestSpeakDefinition(t *testing.T) {
+	model := setupTestTUI()
+	model.config.Definitions = map[string]string{"Haus": "a building people live in"}
+	model.viewport = viewport.New(80, 20)
+	speaker := &recordingSpeaker{}
+	model.speaker = speaker
+	model.startNextWord()
+	model.showInput = true
+
+	updated, cmd := model.Update(tea.KeyMsg{Type: tea.KeyCtrlD})
+	model = updated.(appModel)
+	if cmd == nil {
+		t.Fatal("Ctrl+D should return a speak command")
+	}
+	cmd()
+	if len(speaker.texts) != 1 || speaker.texts[0] != "a building people live in" {
+		t.Errorf("spoken = %v, want the definition", speaker.texts)
+	}
+
+	// A word without definition only shows a note
+	answerCurrentWord(&model, "Haus")
+	model.showInput = true
+	updated, cmd = model.Update(tea.KeyMsg{Type: tea.KeyCtrlD})
+	model = updated.(appModel)
+	if cmd != nil {
+		t.Error("Ctrl+D without definition should not speak")
+	}
+	if !strings.Contains(model.viewport.View(), "no definition") {
+		t.Errorf("view should show the no-definition note, got:\n%s", model.viewport.View())
+	}
+}
+
+// TestResetSession tests that restarting returns the model to a fresh state
+func TestResetSession(t *testing.T) {
+	model := setupTestTUI()
+	model.startNextWord()
+	answerCurrentWord(&model, "Hau")
+	answerCurrentWord(&model, "Buch")
+
+	// From the input state
+	model.showInput = true
+	updated, cmd := model.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	model = updated.(appModel)
+	if cmd == nil {
+		t.Error("restart should speak the first word again")
+	}
+	assertFreshSession(t, model)
+
+	// From the summary screen
+	model.stopEarly()
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	model = updated.(appModel)
+	assertFreshSession(t, model)
+}
+
+// assertFreshSession checks that no progress of an earlier run is left
+func assertFreshSession(t *testing.T, model appModel) {
+	t.Helper()
+	if model.finished || model.stoppedEarly {
+		t.Error("restarted session should not be finished")
+	}
+	if model.wordIndex != 0 || model.correctCount != 0 || model.totalAttempts != 0 || model.score != 0 {
+		t.Errorf("counters not reset: index %d, correct %d, attempts %d, score %d",
+			model.wordIndex, model.correctCount, model.totalAttempts, model.score)
+	}
+	if len(model.correctWords) != 0 || len(model.attempts) != 0 {
+		t.Error("word progress not reset")
+	}
+	if len(model.words) != 3 {
+		t.Errorf("queue has %d words, want the 3 original words", len(model.words))
+	}
+	if model.currentWord == "" || model.dialogState != dialogHidden {
+		t.Error("restarted session should be practicing the first word")
+	}
+}
+
+// TestShowLength tests the letter count hint in the prompt
+func TestShowLength(t *testing.T) {
+	localizer, _ := initI18n("en")
+	config := setupTestConfig()
+	config.ShowLength = true
+	model := initialAppModel(localizer, config, []string{"Füße"})
+	model.speaker = &recordingSpeaker{}
+	model.viewport = viewport.New(80, 20)
+	model.startNextWord()
+	model.showInput = true
+	model.updateViewportContent()
+
+	// Umlauts and ß count as one letter each
+	view := model.viewport.View()
+	if !strings.Contains(view, "____ ") || !strings.Contains(view, "(4 letters)") {
+		t.Errorf("prompt should show 4 blanks and the count, got:\n%s", view)
+	}
+	if strings.Contains(view, "Füße") {
+		t.Error("the length hint must not reveal the word")
+	}
+
+	// Phrases get one count per word
+	if got := lengthHint("der Bär", localizer); !strings.Contains(got, "___ ___") || !strings.Contains(got, "(3, 3 letters)") {
+		t.Errorf("lengthHint(phrase) = %q", got)
+	}
+}
+
+// TestInterWordPause tests the pause between closing a dialog and the next word
+func TestInterWordPause(t *testing.T) {
+	model := setupTestTUI()
+	model.config.InterWordPause = time.Millisecond
+	speaker := &recordingSpeaker{}
+	model.speaker = speaker
+	model.viewport = viewport.New(80, 20)
+	model.startNextWord()
+
+	model.currentWord = model.words[0]
+	model.validateInput(model.currentWord)
+	cmd := model.handleDialogClose()
+	if !model.pausing || model.showInput {
+		t.Fatal("closing the dialog should pause before the next word")
+	}
+	if !strings.Contains(model.viewport.View(), "Next word...") {
+		t.Errorf("pause should show the indicator, got:\n%s", model.viewport.View())
+	}
+
+	// The tick starts the next word
+	msg := cmd()
+	if _, ok := msg.(interWordPauseMsg); !ok {
+		t.Fatalf("pause command sent %T, want interWordPauseMsg", msg)
+	}
+	updated, speak := model.Update(msg)
+	model = updated.(appModel)
+	if model.pausing || model.currentWord != model.words[1] || speak == nil {
+		t.Error("after the pause the next word should be spoken")
+	}
+
+	// No pause before the summary
+	model.wordIndex = len(model.words) - 1
+	model.currentWord = model.words[model.wordIndex]
+	model.validateInput(model.currentWord)
+	model.handleDialogClose()
+	if model.pausing || !model.finished {
+		t.Error("the last word should lead straight to the summary")
+	}
+}
+
+// TestGiveUpSpellsWord tests that the word is spelled out after the last allowed attempt
+func TestGiveUpSpellsWord(t *testing.T) {
+	oldPause := spellLetterPause
+	spellLetterPause = 0
+	t.Cleanup(func() { spellLetterPause = oldPause })
+
+	model := setupTestTUI()
+	model.config.MaxAttempts = 2
+	speaker := &recordingSpeaker{}
+	model.speaker = speaker
+	model.currentWord = model.words[0]
+
+	// The first miss is just a miss
+	if _, cmd := model.validateInput("Hau"); cmd != nil || model.givenUp {
+		t.Fatal("the first miss should not spell the word")
+	}
+	model.handleDialogClose()
+	model.wordIndex = 0
+	model.currentWord = model.words[0]
+
+	_, cmd := model.validateInput("Hau")
+	if !model.givenUp || !model.spelling {
+		t.Fatal("the last allowed miss should spell the word out")
+	}
+	if !strings.Contains(model.renderDialog(), "spell it together") {
+		t.Error("dialog should announce the spelling")
+	}
+
+	// Every letter is spoken in order, one message at a time
+	for cmd != nil {
+		updated, next := model.Update(cmd())
+		model = updated.(appModel)
+		cmd = next
+	}
+	if got := strings.Join(speaker.texts, ","); got != "H,a,u,s" {
+		t.Errorf("spoken letters = %s, want H,a,u,s", got)
+	}
+	for _, rate := range speaker.rates {
+		if rate != slowRate {
+			t.Errorf("letters should be spoken slowly, got rate %d", rate)
+		}
+	}
+	if model.spelling {
+		t.Error("spelling should end after the last letter")
+	}
+
+	// The given-up word is not asked again
+	queued := len(model.words)
+	model.handleDialogClose()
+	if len(model.words) != queued {
+		t.Errorf("given-up word was requeued: %v", model.words)
+	}
+}
+
+// TestSessionResult tests the outcome recorded in the history
+func TestSessionResult(t *testing.T) {
+	model := setupTestTUI()
+	model.startNextWord()
+	answerCurrentWord(&model, "Hau")
+	answerCurrentWord(&model, model.words[model.wordIndex])
+	model.stopEarly()
+
+	result := model.sessionResult()
+	if result.TotalAttempts != 2 || result.CorrectCount != 1 || result.Accuracy != 50 {
+		t.Errorf("result = %+v, want 2 attempts, 1 correct, 50%%", result)
+	}
+	if result.Completed {
+		t.Error("a session stopped early is not completed")
+	}
+	if len(result.Words) != 2 {
+		t.Fatalf("result should only list the practiced words, got %+v", result.Words)
+	}
+	for _, word := range result.Words {
+		if word.Word == "Haus" && word.Correct {
+			t.Error("Haus was only misspelled")
+		}
+	}
+}
+
+// TestConfirmSubmit tests that the first Enter asks and the second one submits
+func TestConfirmSubmit(t *testing.T) {
+	model := setupTestTUI()
+	model.config.ConfirmSubmit = true
+	model.speaker = &recordingSpeaker{}
+	model.viewport = viewport.New(80, 20)
+	model.startNextWord()
+	model.showInput = true
+	model.inputText = "Haus"
+
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+	updated, _ := model.Update(enter)
+	model = updated.(appModel)
+	if !model.confirming || model.totalAttempts != 0 {
+		t.Fatal("the first Enter should ask for confirmation")
+	}
+	if !strings.Contains(model.viewport.View(), "Submit 'Haus'?") {
+		t.Errorf("confirmation should show the answer, got:\n%s", model.viewport.View())
+	}
+
+	// Esc goes back to editing
+	updated, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	model = updated.(appModel)
+	if model.confirming || cmd != nil || model.inputText != "Haus" {
+		t.Error("Esc should return to editing without quitting")
+	}
+
+	updated, _ = model.Update(enter)
+	model = updated.(appModel)
+	next, _ := model.Update(enter)
+	if m := next.(*appModel); m.totalAttempts != 1 || m.dialogType != dialogCorrect {
+		t.Error("the second Enter should submit the answer")
+	}
+}
+
+// TestTogglePhonetics tests showing the IPA transcription with Ctrl+P
+func TestTogglePhonetics(t *testing.T) {
+	model := setupTestTUI()
+	model.config.Phonetics = map[string]string{"Haus": "haʊs"}
+	model.speaker = &recordingSpeaker{}
+	model.viewport = viewport.New(80, 20)
+	model.startNextWord()
+	model.showInput = true
+	model.updateViewportContent()
+	if strings.Contains(model.viewport.View(), "/haʊs/") {
+		t.Fatal("the transcription should be hidden until toggled")
+	}
+
+	ctrlP := tea.KeyMsg{Type: tea.KeyCtrlP}
+	updated, _ := model.Update(ctrlP)
+	model = updated.(appModel)
+	if !strings.Contains(model.viewport.View(), "/haʊs/") {
+		t.Errorf("Ctrl+P should show the transcription, got:\n%s", model.viewport.View())
+	}
+	if model.inputText != "" {
+		t.Error("Ctrl+P must not type anything")
+	}
+
+	// Words without a transcription say so
+	model.currentWord = "Buch"
+	model.updateViewportContent()
+	if !strings.Contains(model.viewport.View(), "No transcription available") {
+		t.Errorf("missing transcription should be noted, got:\n%s", model.viewport.View())
+	}
+
+	updated, _ = model.Update(ctrlP)
+	model = updated.(appModel)
+	if strings.Contains(model.viewport.View(), "transcription") {
+		t.Error("a second Ctrl+P should hide the transcription again")
+	}
+}
+
+// TestSiblingWordNote tests the note when another word of the list was typed
+func TestSiblingWordNote(t *testing.T) {
+	localizer, _ := initI18n("en")
+	config := setupTestConfig()
+	config.Words = testEntries("to", "too", "two")
+	model := initialAppModel(localizer, config, []string{"to", "too", "two"})
+	model.currentWord = "to"
+
+	model.validateInput("too")
+	if !strings.Contains(model.renderDialog(), "different valid word") {
+		t.Error("typing a sibling word should add the note")
+	}
+
+	model.validateInput("tu")
+	if strings.Contains(model.renderDialog(), "different valid word") {
+		t.Error("a word that isn't in the list should not add the note")
+	}
+
+	config.SiblingHint = false
+	model.validateInput("two")
+	if strings.Contains(model.renderDialog(), "different valid word") {
+		t.Error("the note should be off with sibling_hint: false")
+	}
+}
+
+// TestTeacherOverride tests accepting a wrong answer with Ctrl+G
+func TestTeacherOverride(t *testing.T) {
+	model := setupTestTUI()
+	model.speaker = &recordingSpeaker{}
+	model.startNextWord()
+	model.validateInput("Hauss")
+
+	ctrlG := tea.KeyMsg{Type: tea.KeyCtrlG}
+
+	// Disabled by default
+	updated, _ := model.Update(ctrlG)
+	model = updated.(appModel)
+	if model.dialogState != dialogShowing || model.correctCount != 0 {
+		t.Fatal("Ctrl+G should do nothing unless allow_teacher_override is set")
+	}
+
+	model.config.AllowTeacherOverride = true
+	updated, _ = model.Update(ctrlG)
+	m := updated.(appModel)
+	if m.correctCount != 1 || m.misses["Haus"] != 0 {
+		t.Errorf("override: correct %d, misses %d, want 1 and 0", m.correctCount, m.misses["Haus"])
+	}
+	if len(m.correctWords) != 1 || m.correctWords[0] != "Haus" {
+		t.Errorf("correctWords = %v, want [Haus]", m.correctWords)
+	}
+	if m.score != m.config.Scoring.FirstTry {
+		t.Errorf("score = %d, want the first-try points %d", m.score, m.config.Scoring.FirstTry)
+	}
+	// The word counts as done: no requeue, and the next word is up
+	if len(m.words) != 3 || m.currentWord != "Buch" || m.manualOverride {
+		t.Errorf("override should move on without requeueing, queue %v, current %q", m.words, m.currentWord)
+	}
+}
+
+// TestSortCompletedWords tests the alphabetical list in the title bar
+func TestSortCompletedWords(t *testing.T) {
+	model := setupTestTUI()
+	model.language = "de"
+	model.width = 200
+	model.correctWords = []string{"Zug", "Äpfel", "Baum"}
+
+	if bar := model.renderTitleBar(); !strings.Contains(bar, "Zug, Äpfel, Baum") {
+		t.Errorf("words should be in completion order by default, got:\n%s", bar)
+	}
+	model.config.SortCompletedWords = true
+	if bar := model.renderTitleBar(); !strings.Contains(bar, "Äpfel, Baum, Zug") {
+		t.Errorf("words should be sorted, got:\n%s", bar)
+	}
+}
+
+// TestDifficultyInTitle tests that the title bar shows the difficulty of
+// the current word, if it has one
+func TestDifficultyInTitle(t *testing.T) {
+	model := setupTestTUI()
+	model.width = 200
+	model.entries = map[string]WordEntry{
+		"Rhythmus": {Word: "Rhythmus", Difficulty: "hard"},
+		"Haus":     {Word: "Haus"},
+	}
+
+	model.currentWord = "Rhythmus"
+	if bar := model.renderTitleBar(); !strings.Contains(bar, "Word 1 (hard):") {
+		t.Errorf("title bar should show the difficulty, got:\n%s", bar)
+	}
+
+	model.currentWord = "Haus"
+	if bar := model.renderTitleBar(); !strings.Contains(bar, "Word 1:") {
+		t.Errorf("words without a difficulty should show none, got:\n%s", bar)
+	}
+
+	model.currentWord = "Rhythmus"
+	model.config.ShowDifficulty = false
+	if bar := model.renderTitleBar(); strings.Contains(bar, "hard") {
+		t.Errorf("show_difficulty: false should hide it, got:\n%s", bar)
+	}
+}
+
+// TestWarmupDoesNotCount tests that warm-up answers don't change the results
+func TestWarmupDoesNotCount(t *testing.T) {
+	model := setupTestTUI()
+	model.speaker = &recordingSpeaker{}
+	model.startWarmup([]string{"Buch", "Haus"})
+	model.startNextWord()
+	if !model.warmingUp || model.currentWord != "Buch" {
+		t.Fatalf("session should start with the warm-up, current word %q", model.currentWord)
+	}
+	if bar := model.renderTitleBar(); !strings.Contains(bar, "Warm-up 1 of 2") {
+		t.Errorf("title bar should show the warm-up, got:\n%s", bar)
+	}
+
+	answerCurrentWord(&model, "Buch")
+	if !model.warmingUp || model.correctCount != 0 || model.firstTryCorrect != 0 || model.totalAttempts != 0 {
+		t.Errorf("warm-up counted while running: correct %d, first try %d, attempts %d",
+			model.correctCount, model.firstTryCorrect, model.totalAttempts)
+	}
+	if model.score != 0 || model.currentStreak != 0 || model.bestStreak != 0 || len(model.correctWords) != 0 {
+		t.Errorf("warm-up scored while running: score %d, streak %d, best %d, words %v",
+			model.score, model.currentStreak, model.bestStreak, model.correctWords)
+	}
+	// Stopping now saves a session without any warm-up results, so Buch
+	// is still a first try after resuming
+	if state := model.sessionState(); state.Score != 0 || state.BestStreak != 0 || state.Attempts["Buch"] != 0 {
+		t.Errorf("saved session has warm-up results: %+v", state)
+	}
+	answerCurrentWord(&model, "Hau") // Not repeated in the warm-up
+	if model.warmingUp {
+		t.Fatal("the warm-up should end after its words")
+	}
+	if model.correctCount != 0 || model.totalAttempts != 0 || len(model.correctWords) != 0 {
+		t.Errorf("warm-up counted: correct %d, attempts %d", model.correctCount, model.totalAttempts)
+	}
+	if strings.Join(model.words, ",") != "Haus,Buch,Schule" || model.currentWord != "Haus" {
+		t.Errorf("graded queue = %v, current %q", model.words, model.currentWord)
+	}
+
+	answerCurrentWord(&model, "Haus")
+	if model.correctCount != 1 {
+		t.Errorf("graded answers should count, correct = %d", model.correctCount)
+	}
+	model.stopEarly()
+	if !strings.Contains(model.renderSummary(), "warm-up of 2") {
+		t.Error("summary should mention the warm-up")
+	}
+}
+
+// TestQuietCorrect tests that correct answers skip the dialog
+func TestQuietCorrect(t *testing.T) {
+	model := setupTestTUI()
+	model.config.QuietCorrect = true
+	model.speaker = &recordingSpeaker{}
+	model.width = 120
+	model.startNextWord()
+
+	model.validateInput("Haus")
+	if model.dialogState == dialogShowing {
+		t.Fatal("a correct answer should not show the dialog")
+	}
+	if model.currentWord != "Buch" || model.correctCount != 1 {
+		t.Errorf("should move on to the next word, current %q", model.currentWord)
+	}
+	if !strings.Contains(model.renderTitleBar(), "✔") {
+		t.Error("title bar should flash a check mark")
+	}
+
+	// The check mark disappears after a moment
+	updated, _ := model.Update(quietCheckDoneMsg{attempt: model.submissions})
+	model = updated.(appModel)
+	if strings.Contains(model.renderTitleBar(), "✔") {
+		t.Error("check mark should disappear again")
+	}
+
+	// Wrong answers still get the dialog
+	model.validateInput("Bu")
+	if model.dialogState != dialogShowing {
+		t.Error("a wrong answer should show the dialog")
+	}
+}
+
+// TestSpeakIntro tests that the session is announced before the first word
+func TestSpeakIntro(t *testing.T) {
+	model := setupTestTUI()
+	model.config.SpeakIntro = true
+	speaker := &recordingSpeaker{}
+	model.speaker = speaker
+
+	model.startNextWord()()
+	want := []string{"You will practice 3 word(s).", model.currentWord}
+	if !reflect.DeepEqual(speaker.texts, want) {
+		t.Errorf("spoken %q, want %q", speaker.texts, want)
+	}
+
+	// Only the first word is announced
+	model.wordIndex++
+	model.startNextWord()()
+	if len(speaker.texts) != 3 {
+		t.Errorf("intro should be spoken once, got %q", speaker.texts)
+	}
+}
+
+// TestMixedLanguageComparison tests that a word is compared by the rules
+// of its own language, not those of the list
+func TestMixedLanguageComparison(t *testing.T) {
+	config := setupTestConfig()
+	config.Numbers = true
+	config.SzEquivalence = true
+	config.OptionalLeadingArticle = true
+	config.Words = []WordEntry{
+		{Word: "3", Language: "de"},
+		{Word: "Straße", Language: "de"},
+		{Word: "das Haus", Language: "de"},
+		{Word: "3 cats"},
+	}
+	localizer, _ := initI18n("en")
+
+	tests := []struct {
+		word, input string
+	}{
+		{"3", "drei"},          // German numbers in an English list
+		{"Straße", "Strasse"},  // ß/ss only applies to German
+		{"das Haus", "Haus"},   // German articles are optional
+		{"3 cats", "three cats"},
+	}
+	for _, tt := range tests {
+		model := initialAppModel(localizer, config, []string{tt.word})
+		model.speaker = &recordingSpeaker{}
+		model.startNextWord()
+		model.validateInput(tt.input)
+		if model.dialogType == dialogIncorrect {
+			t.Errorf("%q should be accepted for %q", tt.input, tt.word)
+		}
+	}
+}
+
+// TestRecapMissed tests that missed words are practiced again at the end
+func TestRecapMissed(t *testing.T) {
+	model := setupTestTUI()
+	model.config.RecapMissed = true
+	model.config.GroupSummaryByInitial = true
+	model.config.NoShuffle = true
+	model.speaker = &recordingSpeaker{}
+	model.width = 120
+	model.startNextWord()
+
+	// Miss "Haus" once, then spell everything correctly
+	model.validateInput("Hau")
+	_ = model.handleDialogClose()
+	for i := 0; i < 3 && !model.recapping; i++ {
+		model.validateInput(model.currentWord)
+		_ = model.handleDialogClose()
+	}
+	if !model.recapping || model.finished {
+		t.Fatal("the missed word should start a recap")
+	}
+	if model.currentWord != "Haus" || len(model.words) != 1 {
+		t.Errorf("recap should only have Haus, got %q", model.words)
+	}
+	if !strings.Contains(model.renderTitleBar(), "Recap") {
+		t.Error("title bar should show the recap")
+	}
+
+	model.validateInput("Haus")
+	_ = model.handleDialogClose()
+	if !model.finished {
+		t.Fatal("session should finish after the recap")
+	}
+	summary := model.renderSummary()
+	if !strings.Contains(summary, "Recap: 100% correct (1 word(s))") {
+		t.Errorf("summary should show the recap accuracy, got:\n%s", summary)
+	}
+	// The recap doesn't make the session forget the missed word
+	if !strings.Contains(summary, "H: Haus") {
+		t.Errorf("summary should still group the missed Haus, got:\n%s", summary)
+	}
+}
+
+// TestMasteryLoop tests that missed words are drilled again until each
+// was spelled correctly once
+func TestMasteryLoop(t *testing.T) {
+	model := setupTestTUI()
+	model.config.MasteryLoop = true
+	model.config.SinglePass = true // Misses aren't requeued within a round
+	model.config.NoShuffle = true
+	model.speaker = &recordingSpeaker{}
+	model.width = 120
+	model.startNextWord()
+
+	// Miss "Haus" in the first round, spell the rest correctly
+	model.validateInput("Hau")
+	_ = model.handleDialogClose()
+	for i := 0; i < 2; i++ {
+		model.validateInput(model.currentWord)
+		_ = model.handleDialogClose()
+	}
+	if model.finished || model.masteryRounds != 1 {
+		t.Fatalf("the missed word should start a mastery round (rounds: %d)", model.masteryRounds)
+	}
+	if model.currentWord != "Haus" || len(model.words) != 1 {
+		t.Errorf("the mastery round should only have Haus, got %q", model.words)
+	}
+	if !strings.Contains(model.renderTitleBar(), "Mastery round 1") {
+		t.Error("title bar should show the mastery round")
+	}
+
+	// Correct in the second round: every word is mastered
+	model.validateInput("Haus")
+	_ = model.handleDialogClose()
+	if !model.finished {
+		t.Fatal("session should finish once every word was spelled correctly")
+	}
+	if summary := model.renderSummary(); !strings.Contains(summary, "Rounds until every word was right: 2") {
+		t.Errorf("summary should show the rounds needed, got:\n%s", summary)
+	}
+}
+
+// TestCapitalizationOnlyFeedback tests the encouraging message for wrong case
+func TestCapitalizationOnlyFeedback(t *testing.T) {
+	model := setupTestTUI()
+	model.speaker = &recordingSpeaker{}
+	model.startNextWord()
+
+	model.validateInput("haus")
+	if model.dialogType != dialogIncorrect || !model.capitalizationOnly {
+		t.Fatal("haus for Haus should be wrong, with the capitalization hint")
+	}
+	dialog := model.renderDialog()
+	if !strings.Contains(dialog, "check your capitalization") || strings.Contains(dialog, "Incorrect spelling") {
+		t.Errorf("dialog should show the capitalization message, got:\n%s", dialog)
+	}
+
+	// Other mistakes still get the generic message
+	_ = model.handleDialogClose()
+	model.validateInput("Bu")
+	if model.capitalizationOnly || !strings.Contains(model.renderDialog(), "Incorrect spelling") {
+		t.Error("a wrong letter should show the generic message")
+	}
+}
+
+// TestSessionStateRoundTrip tests saving and resuming a named session
+func TestSessionStateRoundTrip(t *testing.T) {
+	model := setupTestTUI()
+	model.config.NoShuffle = true
+	model.speaker = &recordingSpeaker{}
+	model.startNextWord()
+
+	// Spell "Haus" correctly and quit while its dialog still shows
+	model.validateInput("Haus")
+	state := model.sessionState()
+
+	path := filepath.Join(t.TempDir(), "sessions", "homework.json")
+	if err := saveSessionState(path, state); err != nil {
+		t.Fatalf("saveSessionState() error = %v", err)
+	}
+	loaded, err := loadSessionState(path)
+	if err != nil {
+		t.Fatalf("loadSessionState() error = %v", err)
+	}
+	if !reflect.DeepEqual(loaded, state) {
+		t.Errorf("loaded state = %+v, want %+v", loaded, state)
+	}
+
+	// The completed word is skipped on resume, the stats carry over
+	localizer, _ := initI18n("en")
+	config := setupTestConfig()
+	config.Words = testEntries("Haus", "Buch", "Schule")
+	resumed := initialAppModel(localizer, config, config.wordList())
+	resumed.speaker = &recordingSpeaker{}
+	if !resumed.resumeSession(loaded) {
+		t.Fatal("resumeSession() should resume the saved session")
+	}
+	resumed.startNextWord()
+	if resumed.currentWord != "Buch" || len(resumed.words) != 2 {
+		t.Errorf("should continue with Buch, Schule, got %q", resumed.words)
+	}
+	if resumed.correctCount != 1 || resumed.totalAttempts != 1 || resumed.correctWords[0] != "Haus" {
+		t.Errorf("stats should carry over, got %d correct of %d", resumed.correctCount, resumed.totalAttempts)
+	}
+
+	// A session that was never saved is reported as missing
+	if _, err := loadSessionState(filepath.Join(t.TempDir(), "new.json")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("loadSessionState() error = %v, want os.ErrNotExist", err)
+	}
+}
+
+// TestSpeakRhyme tests that Ctrl+Y speaks the rhyme of the current word
+func TestSpeakRhyme(t *testing.T) {
+	model := setupTestTUI()
+	model.config.NoShuffle = true
+	model.config.Rhymes = map[string]string{"Haus": "Maus"}
+	model.viewport = viewport.New(80, 20)
+	speaker := &recordingSpeaker{}
+	model.speaker = speaker
+	model.startNextWord()
+	model.showInput = true
+
+	updated, cmd := model.Update(tea.KeyMsg{Type: tea.KeyCtrlY})
+	model = updated.(appModel)
+	if cmd == nil {
+		t.Fatal("Ctrl+Y should return a speak command")
+	}
+	cmd()
+	if len(speaker.texts) != 1 || speaker.texts[0] != "Maus" {
+		t.Errorf("spoken = %v, want the rhyme", speaker.texts)
+	}
+
+	// A word without rhyme only shows a note
+	answerCurrentWord(&model, "Haus")
+	model.showInput = true
+	updated, cmd = model.Update(tea.KeyMsg{Type: tea.KeyCtrlY})
+	model = updated.(appModel)
+	if cmd != nil {
+		t.Error("Ctrl+Y without rhyme should not speak")
+	}
+	if !strings.Contains(model.viewport.View(), "no rhyme") {
+		t.Errorf("view should show the no-rhyme note, got:\n%s", model.viewport.View())
+	}
+}
+
+// TestTranscript tests the transcript pane of the answers
+func TestTranscript(t *testing.T) {
+	model := setupTestTUI()
+	model.config.NoShuffle = true
+	model.config.ShowTranscript = true
+	model.speaker = &recordingSpeaker{}
+	updated, _ := model.Update(tea.WindowSizeMsg{Width: 120, Height: 12})
+	model = updated.(appModel)
+	model.startNextWord()
+
+	for _, input := range []string{"Haus", "Bch", "Schule", "Buch"} {
+		answerCurrentWord(&model, input)
+	}
+	want := []transcriptEntry{
+		{word: "Haus", input: "Haus", correct: true},
+		{word: "Buch", input: "Bch", correct: false},
+		{word: "Schule", input: "Schule", correct: true},
+		{word: "Buch", input: "Buch", correct: true},
+	}
+	if !reflect.DeepEqual(model.transcript, want) {
+		t.Errorf("transcript = %+v, want %+v", model.transcript, want)
+	}
+
+	// In 6 lines there is room for 3 answers next to border and title
+	pane := model.renderTranscript(6)
+	if strings.Contains(pane, "✔ Haus") || !strings.Contains(pane, "✘ Bch → Buch") || !strings.Contains(pane, "✔ Buch") {
+		t.Errorf("pane should scroll to the newest answers, got:\n%s", pane)
+	}
+	if model.viewport.Width != 120-transcriptWidth {
+		t.Errorf("viewport width = %d, want room for the pane", model.viewport.Width)
+	}
+
+	// Narrow terminals have no room for the pane
+	model.width = 80
+	if model.showsTranscript() {
+		t.Error("pane should be hidden in narrow terminals")
+	}
+}
+
+// TestTargetAccuracy tests ending the session early at the target accuracy
+func TestTargetAccuracy(t *testing.T) {
+	localizer, _ := initI18n("en")
+	config := setupTestConfig()
+	config.NoShuffle = true
+	config.TargetAccuracy = 75
+	config.MinWords = 4
+	model := initialAppModel(localizer, config, []string{"Haus", "Buch", "Schule", "Tisch", "Stuhl"})
+	model.speaker = &recordingSpeaker{}
+	model.startNextWord()
+
+	// 100%, 50%, 66%: below the minimum answers or the target
+	for _, input := range []string{"Haus", "Bch", "Schule"} {
+		answerCurrentWord(&model, input)
+		if model.finished {
+			t.Fatalf("session ended early after %q", input)
+		}
+	}
+
+	// 3 of 4 answers correct reaches 75%
+	answerCurrentWord(&model, "Tisch")
+	if !model.finished || !model.mastered {
+		t.Fatal("session should end once the target accuracy is reached")
+	}
+	if summary := model.renderSummary(); !strings.Contains(summary, "Mastery achieved!") {
+		t.Errorf("summary should announce the mastery, got:\n%s", summary)
+	}
+}
+
+// failingSpeaker is a Speaker whose speech command always fails
+type failingSpeaker struct{}
+
+func (failingSpeaker) Speak(text, langCode string, rate int) error {
+	return errors.New("say: voice not found")
+}
+
+// TestTTSFailureIndicator tests that repeated speech errors show in the title bar
+func TestTTSFailureIndicator(t *testing.T) {
+	model := setupTestTUI()
+	model.width = 120
+	model.speaker = failingSpeaker{}
+
+	speakOnce := func() {
+		updated, _ := model.Update(model.repeatAudio(defaultRate)())
+		model = updated.(appModel)
+	}
+	updated, _ := model.Update(model.startNextWord()())
+	model = updated.(appModel)
+	speakOnce()
+	if strings.Contains(model.renderTitleBar(), "Audio unavailable") {
+		t.Error("two failures should not show the indicator yet")
+	}
+
+	speakOnce()
+	if model.ttsFailures != ttsFailureLimit || !strings.Contains(model.renderTitleBar(), "Audio unavailable") {
+		t.Errorf("repeated failures should show the indicator (%d failures)", model.ttsFailures)
+	}
+
+	// A successful command clears it
+	model.speaker = &recordingSpeaker{}
+	speakOnce()
+	if model.ttsFailures != 0 || strings.Contains(model.renderTitleBar(), "Audio unavailable") {
+		t.Error("the indicator should disappear once speech works again")
+	}
+}
+
+// TestCopyMode tests that copy mode shows the word while it is typed
+func TestCopyMode(t *testing.T) {
+	model := setupTestTUI()
+	model.config.Mode = modeCopy
+	model.config.NoShuffle = true
+	model.viewport = viewport.New(80, 20)
+	model.speaker = &recordingSpeaker{}
+	updated, _ := model.Update(model.startNextWord()())
+	model = updated.(appModel)
+
+	model.inputText = "Ha"
+	model.updateViewportContent()
+	view := model.viewport.View()
+	if !strings.Contains(view, "Copy the word") || !strings.Contains(view, "Haus") {
+		t.Errorf("copy mode should show the word during input, got:\n%s", view)
+	}
+
+	// Answers are still graded with a diff
+	model.validateInput("Hause")
+	if model.dialogType != dialogIncorrect || model.dialogDiff == "" {
+		t.Error("a wrong copy should be graded with a diff")
+	}
+}
+
+// TestTimeWarning tests that the hurry warning is spoken exactly once as
+// the countdown crosses TimeWarning
+func TestTimeWarning(t *testing.T) {
+	original := countdownInterval
+	countdownInterval = time.Millisecond
+	t.Cleanup(func() { countdownInterval = original })
+
+	model := setupTestTUI()
+	model.config.TimeLimit = 5 * time.Millisecond
+	model.config.TimeWarning = 3 * time.Millisecond
+	speaker := &recordingSpeaker{}
+	model.speaker = speaker
+	updated, cmd := model.Update(model.startNextWord()())
+	model = updated.(appModel)
+
+	// Run the countdown tick by tick until one tick is left; a tick
+	// with a due warning comes back batched with it
+	// A tea.Tick command can only be run once, so its message is kept
+	tick := cmd()
+	for model.timeLeft > countdownInterval {
+		updated, cmd = model.Update(tick)
+		model = updated.(appModel)
+		msg := cmd()
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			for _, c := range batch {
+				if next, ok := c().(countdownMsg); ok {
+					msg = next
+				}
+			}
+		}
+		tick = msg
+	}
+
+	warnings := 0
+	for _, text := range speaker.texts {
+		if text == "Hurry!" {
+			warnings++
+		}
+	}
+	if warnings != 1 {
+		t.Errorf("expected the warning once, got %d times (spoken: %v)", warnings, speaker.texts)
+	}
+	if !model.timeWarned {
+		t.Error("the model should remember that the learner was warned")
+	}
+
+	// Running out of time checks the (empty) input as a wrong answer
+	updated, _ = model.Update(tick)
+	if final := updated.(*appModel); final.dialogType != dialogIncorrect || final.totalAttempts != 1 {
+		t.Errorf("time up should count as a wrong answer, got type %v after %d attempts", final.dialogType, final.totalAttempts)
+	}
+}
+
+// TestTimeUpChooseMode tests that running out of time in choose mode
+// checks the highlighted option
+func TestTimeUpChooseMode(t *testing.T) {
+	seedRandom(7)
+
+	model := setupTestTUI()
+	model.config.Mode = modeChoose
+	model.config.TimeLimit = time.Second
+	model.viewport = viewport.New(80, 20)
+	model.speaker = &recordingSpeaker{}
+	updated, _ := model.Update(model.startNextWord()())
+	model = updated.(appModel)
+	for model.choices[model.choiceIndex] != model.currentWord {
+		updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyDown})
+		model = updated.(appModel)
+	}
+
+	model.timeLeft = countdownInterval
+	result, _ := model.Update(countdownMsg{countdown: model.countdowns})
+	if final := result.(*appModel); final.dialogType != dialogCorrect {
+		t.Errorf("the highlighted right spelling should count when time is up, got dialog type %v", final.dialogType)
+	}
+}
+
+// TestChooseMode tests that the right spelling is always among the
+// choices and that picking it counts as correct
+func TestChooseMode(t *testing.T) {
+	seedRandom(7)
+
+	model := setupTestTUI()
+	model.config.Mode = modeChoose
+	model.viewport = viewport.New(80, 20)
+	model.speaker = &recordingSpeaker{}
+
+	for i := 0; i < 20; i++ {
+		choices := model.choicesFor(WordEntry{Word: "Schule"})
+		if len(choices) != model.config.Choices {
+			t.Fatalf("expected %d choices, got %q", model.config.Choices, choices)
+		}
+		found := 0
+		seen := make(map[string]bool)
+		for _, choice := range choices {
+			if choice == "Schule" {
+				found++
+			}
+			seen[choice] = true
+		}
+		if found != 1 || len(seen) != len(choices) {
+			t.Fatalf("choices should have Schule exactly once and no duplicates, got %q", choices)
+		}
+	}
+
+	updated, _ := model.Update(model.startNextWord()())
+	model = updated.(appModel)
+	if !strings.Contains(model.viewport.View(), "Choose the right spelling") {
+		t.Errorf("choose mode should show its prompt, got:\n%s", model.viewport.View())
+	}
+	// Move down to the right spelling and pick it
+	for model.choices[model.choiceIndex] != model.currentWord {
+		updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyDown})
+		model = updated.(appModel)
+	}
+	result, _ := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if final := result.(*appModel); final.dialogType != dialogCorrect {
+		t.Errorf("picking the right spelling should be correct, got dialog type %v", final.dialogType)
+	}
+}
+
+// TestChoicesNotAccepted tests that no distractor would be accepted as
+// correct, neither an accepted spelling nor a typo that a relaxation
+// rule turns back into the answer
+func TestChoicesNotAccepted(t *testing.T) {
+	seedRandom(3)
+
+	localizer, _ := initI18n("en")
+	config := setupTestConfig()
+	config.Mode = modeChoose
+	config.Choices = 4
+	config.CollapseWhitespace = true
+	config.IgnoreTrailingPunctuation = true
+	config.Words = []WordEntry{{Word: "colour", Accept: []string{"color"}}, {Word: "color"}, {Word: "Hi you!"}, {Word: "Haus"}}
+	model := initialAppModel(localizer, config, config.wordList())
+
+	for _, entry := range []WordEntry{config.Words[0], config.Words[2]} {
+		for i := 0; i < 100; i++ {
+			for _, choice := range model.choicesFor(entry) {
+				if choice == entry.Word {
+					continue
+				}
+				if _, ok := matchAnswer(choice, entry.acceptedAnswers(false), "en", config); ok {
+					t.Fatalf("distractor %q would be accepted for %q", choice, entry.Word)
+				}
+			}
+		}
+	}
+}
+
+// TestRateJitter tests that the speech rate varies per word within the
+// jitter band, reproducibly with a seed
+func TestRateJitter(t *testing.T) {
+	speakWords := func() []int {
+		seedRandom(42)
+		model := setupTestTUI()
+		model.config.RateJitter = 20
+		speaker := &recordingSpeaker{}
+		model.speaker = speaker
+		for model.wordIndex = 0; model.wordIndex < len(model.words); model.wordIndex++ {
+			model.startNextWord()()
+		}
+		return speaker.rates
+	}
+
+	rates := speakWords()
+	if len(rates) != 3 {
+		t.Fatalf("expected a rate per word, got %v", rates)
+	}
+	for _, rate := range rates {
+		if rate < defaultRate-20 || rate > defaultRate+20 {
+			t.Errorf("rate %d is outside %d±20", rate, defaultRate)
+		}
+	}
+	if again := speakWords(); !reflect.DeepEqual(rates, again) {
+		t.Errorf("the same seed should give the same rates, got %v and %v", rates, again)
+	}
+}
+
+// TestFlashFeedback tests the green and red title bar flash after answers
+func TestFlashFeedback(t *testing.T) {
+	model := setupTestTUI()
+	model.config.FlashFeedback = true
+	model.speaker = &recordingSpeaker{}
+	model.startNextWord()
+
+	_, cmd := model.validateInput(model.currentWord)
+	if model.flash != flashGreen || cmd == nil {
+		t.Fatalf("a correct answer should flash green, got %v", model.flash)
+	}
+	updated, _ := model.Update(feedbackFlashDoneMsg{attempt: model.submissions})
+	model = updated.(appModel)
+	if model.flash != flashNone {
+		t.Error("the flash should end when its timer fires")
+	}
+
+	_ = model.handleDialogClose()
+	model.validateInput("xyz")
+	if model.flash != flashRed {
+		t.Errorf("a wrong answer should flash red, got %v", model.flash)
+	}
+
+	// Off by default
+	model = setupTestTUI()
+	model.speaker = &recordingSpeaker{}
+	model.startNextWord()
+	model.validateInput(model.currentWord)
+	if model.flash != flashNone {
+		t.Error("without FlashFeedback the title bar should not flash")
+	}
+}
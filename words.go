@@ -0,0 +1,221 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+	"gopkg.in/yaml.v3"
+)
+
+// WordEntry is a single entry of the word list
+// In YAML an entry is either a plain word ("- Haus") or a mapping with
+// extra metadata ("- {word: Haus, article: das}")
+type WordEntry struct {
+	Word    string `yaml:"word"`
+	Article string `yaml:"article"` // Optional article spoken before the word (e.g., "das")
+
+	// Pronunciation is sent to the speaker instead of the word, for homographs
+	// like "read" (spoken "red") or "live"; the learner still types Word
+	Pronunciation string `yaml:"pronunciation"`
+
+	// Expected is typed instead of the spoken word, for grammar practice
+	// (e.g., "Haus" is spoken and its plural "Häuser" has to be typed);
+	// Instruction tells the learner which form is wanted ("type the plural")
+	Expected    string `yaml:"expected"`
+	Instruction string `yaml:"instruction"`
+
+	// Weight makes a word more (or less) likely to be drawn by --count,
+	// e.g. a frequency score so common words come up more often
+	// Entries without a weight count as 1.0
+	Weight float64 `yaml:"weight"`
+
+	// Language overrides the list language for this word (voice and
+	// writing direction), for mixed vocabulary lists
+	Language string `yaml:"language"`
+
+	// Accept lists other correct spellings ("color" for "colour"); the
+	// word itself is still what is spoken and shown
+	Accept []string `yaml:"accept"`
+
+	// Difficulty is "easy", "medium" or "hard"; --ramp practices easy
+	// words first (entries without one count as medium)
+	Difficulty string `yaml:"difficulty"`
+}
+
+// Difficulty levels, in the order --ramp practices them
+var difficultyLevels = []string{"easy", "medium", "hard"}
+
+// difficultyMessageIDs are the translations of the difficulty levels
+var difficultyMessageIDs = map[string]string{
+	"easy":   "DifficultyEasy",
+	"medium": "DifficultyMedium",
+	"hard":   "DifficultyHard",
+}
+
+// difficultyRank returns the position of the entry's level in
+// difficultyLevels, or -1 for an unknown level
+func (e WordEntry) difficultyRank() int {
+	difficulty := e.Difficulty
+	if difficulty == "" {
+		difficulty = "medium"
+	}
+	for i, level := range difficultyLevels {
+		if level == difficulty {
+			return i
+		}
+	}
+	return -1
+}
+
+// UnmarshalYAML lets an entry be written as a plain string or as a mapping
+// yaml.v3 calls this method automatically for every WordEntry it decodes
+func (e *WordEntry) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		return node.Decode(&e.Word)
+	}
+
+	// Decode into an alias type without this method to avoid infinite recursion
+	type plainEntry WordEntry
+	return node.Decode((*plainEntry)(e))
+}
+
+// spokenText returns what the speaker pronounces: the article and the word,
+// or its pronunciation hint if the entry has one
+func (e WordEntry) spokenText() string {
+	spoken := e.Word
+	if e.Pronunciation != "" {
+		spoken = e.Pronunciation
+	}
+	return withArticle(e.Article, spoken)
+}
+
+// languageOr returns the entry's own language, or fallback if it has none
+func (e WordEntry) languageOr(fallback string) string {
+	if e.Language != "" {
+		return e.Language
+	}
+	return fallback
+}
+
+// weight returns the sampling weight, defaulting to 1.0
+func (e WordEntry) weight() float64 {
+	if e.Weight == 0 {
+		return 1.0
+	}
+	return e.Weight
+}
+
+// target returns what the learner has to type
+// This is the expected form if there is one, otherwise the word itself;
+// the article is only required when requireArticle is set
+func (e WordEntry) target(requireArticle bool) string {
+	if e.Expected != "" {
+		return e.Expected
+	}
+	if requireArticle {
+		return withArticle(e.Article, e.Word)
+	}
+	return e.Word
+}
+
+// acceptedAnswers returns every answer that counts as correct: the target
+// first, then the alternative spellings from Accept
+func (e WordEntry) acceptedAnswers(requireArticle bool) []string {
+	answers := []string{e.target(requireArticle)}
+	for _, alternative := range e.Accept {
+		if requireArticle && e.Expected == "" {
+			alternative = withArticle(e.Article, alternative)
+		}
+		answers = append(answers, alternative)
+	}
+	return answers
+}
+
+// withArticle puts an (optional) article in front of a word
+func withArticle(article, word string) string {
+	if article == "" {
+		return word
+	}
+	return article + " " + word
+}
+
+// wordList returns the plain words of the config in order
+func (c *Config) wordList() []string {
+	words := make([]string, len(c.Words))
+	for i, entry := range c.Words {
+		words[i] = entry.Word
+	}
+	return words
+}
+
+// isSiblingWord reports whether input is another word of the list than
+// word, e.g. "too" for "to" in a list with both homophones
+// It compares like the correctness check, so "Too" counts with ignore_case,
+// each word by the rules of its own language
+func (c *Config) isSiblingWord(input, word string) bool {
+	for _, entry := range c.Words {
+		if entry.Word == word {
+			continue
+		}
+		normalize := buildNormalizer(c, entry.languageOr(c.Language))
+		normalized := normalize(input)
+		if normalized == normalize(entry.Word) || normalized == normalize(entry.target(c.RequireArticle)) {
+			return true
+		}
+	}
+	return false
+}
+
+// entries returns the word entries indexed by word for metadata lookups
+func (c *Config) entries() map[string]WordEntry {
+	entries := make(map[string]WordEntry, len(c.Words))
+	for _, entry := range c.Words {
+		entries[entry.Word] = entry
+	}
+	return entries
+}
+
+// selectWords reorders (and filters) the word list to the given words,
+// e.g. to a shuffled sample for sessions that practice the config in order
+func (c *Config) selectWords(words []string) {
+	entries := c.entries()
+	selected := make([]WordEntry, 0, len(words))
+	for _, word := range words {
+		selected = append(selected, entries[word])
+	}
+	c.Words = selected
+}
+
+// collateWords returns the words sorted alphabetically by the rules of a
+// language, e.g. German puts "Äpfel" next to "Apfel" instead of after "Z"
+// like a plain byte-wise sort.Strings would
+func collateWords(words []string, lang string) []string {
+	tag, err := language.Parse(lang)
+	if err != nil {
+		tag = language.Und // Unknown codes get the language-neutral order
+	}
+	sorted := append([]string(nil), words...)
+	collate.New(tag, collate.IgnoreCase).SortStrings(sorted)
+	return sorted
+}
+
+// groupByInitial sorts words into buckets by their first letter, in upper
+// case, so "haus" and "Hund" share the bucket 'H'
+// Strings are bytes in Go, so the first letter is decoded as a rune: "Übung"
+// goes to 'Ü', not to the first byte of its UTF-8 encoding
+// Words keep their order within a bucket; empty words are skipped
+func groupByInitial(words []string) map[rune][]string {
+	groups := make(map[rune][]string)
+	for _, word := range words {
+		first, size := utf8.DecodeRuneInString(strings.TrimSpace(word))
+		if size == 0 {
+			continue
+		}
+		initial := unicode.ToUpper(first)
+		groups[initial] = append(groups[initial], word)
+	}
+	return groups
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// worksheetBlank is the line a word is written on by hand
+var worksheetBlank = strings.Repeat("_", 40)
+
+// generateWorksheet writes a printable worksheet for the words of cfg, in
+// their order: a numbered blank line per word, with the instruction and
+// definition of the word as a hint where the list has one, followed by an
+// answer key for the teacher
+// The words are read aloud by the teacher, so there is nothing to speak
+func generateWorksheet(w io.Writer, cfg *Config) error {
+	localizer, err := initI18n(cfg.Language)
+	if err != nil {
+		return err
+	}
+
+	title := cfg.Title
+	if title == "" {
+		title = tr(localizer, "Title")
+	}
+	if cfg.Author != "" {
+		title = tr(localizer, "TitleByAuthor", map[string]interface{}{"Title": title, "Author": cfg.Author})
+	}
+
+	// A strings.Builder collects the text, so there is one write (and one
+	// error to check) at the end
+	var sheet strings.Builder
+	sheet.WriteString(title + "\n\n")
+	sheet.WriteString(tr(localizer, "WorksheetName") + "\n\n")
+	for i, entry := range cfg.Words {
+		fmt.Fprintf(&sheet, "%2d. %s\n", i+1, worksheetBlank)
+		if entry.Instruction != "" {
+			fmt.Fprintf(&sheet, "    (%s)\n", entry.Instruction)
+		}
+		if definition := cfg.Definitions[entry.Word]; definition != "" {
+			fmt.Fprintf(&sheet, "    %s\n", definition)
+		}
+		sheet.WriteString("\n")
+	}
+
+	sheet.WriteString(tr(localizer, "WorksheetAnswerKey") + "\n\n")
+	for i, entry := range cfg.Words {
+		fmt.Fprintf(&sheet, "%2d. %s\n", i+1, entry.target(cfg.RequireArticle))
+	}
+
+	_, err = io.WriteString(w, sheet.String())
+	return err
+}